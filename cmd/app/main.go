@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -8,16 +9,25 @@ import (
 
 	"github.com/PosokhovVadim/stawberry/internal/app"
 	"github.com/PosokhovVadim/stawberry/internal/config"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/alert"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/bundle"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/catalog"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/question"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/review"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/watchlist"
 	"github.com/PosokhovVadim/stawberry/internal/handler"
+	"github.com/PosokhovVadim/stawberry/internal/worker"
 	objectstorage "github.com/PosokhovVadim/stawberry/pkg/s3"
 	"github.com/gin-gonic/gin"
 )
 
 // Global variables for application state
 var (
-	router *gin.Engine
+	router  *gin.Engine
+	workers []app.Worker
 )
 
 func main() {
@@ -33,7 +43,7 @@ func main() {
 	}
 
 	// Start server
-	if err := app.StartServer(router, port); err != nil {
+	if err := app.StartServer(router, port, workers...); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -52,15 +62,75 @@ func initializeApp() error {
 	db := repository.InitDB(cfg)
 	productRepository := repository.NewProductRepository(db)
 	offerRepository := repository.NewOfferRepository(db)
-
-	productService := product.NewProductService(productRepository)
-	offerService := offer.NewOfferService(offerRepository)
+	storeRepository := repository.NewStoreRepository(db)
+	catalogRepository := repository.NewCatalogRepository(db)
+	currencyRepository := repository.NewCurrencyRepository(db)
+	userRepository := repository.NewUserRepository(db)
+	watchlistRepository := repository.NewWatchlistRepository(db)
+	alertRepository := repository.NewAlertRepository(db)
+	questionRepository := repository.NewQuestionRepository(db)
+	reviewRepository := repository.NewReviewRepository(db)
+	bundleRepository := repository.NewBundleRepository(db)
 
 	// Initialize object storage s3
 	s3 := objectstorage.ObjectStorageConn(cfg)
 
+	currencyService := currency.NewCurrencyService(currencyRepository, userRepository)
+	if cfg.ExchangeRatesFile != "" {
+		if err := currencyService.LoadRatesFile(cfg.ExchangeRatesFile); err != nil {
+			return fmt.Errorf("failed to load exchange rates: %w", err)
+		}
+	}
+
+	productService := product.NewProductService(
+		productRepository,
+		storeRepository,
+		userRepository,
+		s3,
+		currencyService,
+		cfg.ImageMaxSize,
+	)
+	bundleService := bundle.NewBundleService(bundleRepository, storeRepository, storeRepository, currencyService)
+	offerService := offer.NewOfferService(
+		offerRepository,
+		productRepository,
+		storeRepository,
+		bundleService,
+		storeRepository,
+		currencyService,
+		cfg.OfferTTL,
+		cfg.ReservationTTL,
+		cfg.OfferMaxRounds,
+	)
+	catalogService := catalog.NewCatalogService(catalogRepository, storeRepository, s3, cfg.ImportMaxSize)
+	watchlistService := watchlist.NewWatchlistService(watchlistRepository)
+	alertService := alert.NewAlertService(alertRepository)
+	questionService := question.NewQuestionService(questionRepository, storeRepository, userRepository)
+	reviewService := review.NewReviewService(reviewRepository, s3, cfg.ImageMaxSize)
+
 	// Initialize router
-	router = handler.SetupRouter(productService, offerService, s3)
+	router = handler.SetupRouter(
+		productService,
+		offerService,
+		catalogService,
+		currencyService,
+		watchlistService,
+		alertService,
+		questionService,
+		reviewService,
+		bundleService,
+		s3,
+	)
+
+	// Initialize background workers
+	workers = []app.Worker{
+		worker.NewReservationReleaser(offerService, cfg.ReservationSweepInterval, cfg.ReservationBatchSize),
+		worker.NewOfferExpirer(offerService, cfg.OfferExpireInterval, cfg.OfferExpireBatchSize),
+		worker.NewImageProcessor(productService, cfg.ImageProcessInterval, cfg.ImageProcessBatchSize),
+		worker.NewCatalogImporter(catalogService, cfg.ImportInterval, cfg.ImportBatchSize),
+		worker.NewRelatedRefresher(productService, cfg.RelatedRefreshInterval, cfg.RelatedRefreshBatchSize),
+		worker.NewProductPublisher(productService, cfg.PublishInterval, cfg.PublishBatchSize),
+	}
 
 	return nil
 }
@@ -7,19 +7,41 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// startServer starts the HTTP server with graceful shutdown
-func StartServer(router *gin.Engine, port string) error {
+// Worker is a background job that runs until its context is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// startServer starts the HTTP server and background workers with graceful shutdown
+func StartServer(router *gin.Engine, port string, workers ...Worker) error {
 	srv := &http.Server{
 		Addr:    ":" + port,
 		Handler: router,
 	}
 
+	// Start background workers, they are stopped when the server stops
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w Worker) {
+			defer wg.Done()
+			w.Run(workersCtx)
+		}(w)
+	}
+	defer func() {
+		stopWorkers()
+		wg.Wait()
+		log.Println("Background workers stopped")
+	}()
+
 	// Channel to listen for errors coming from the server
 	serverErrors := make(chan error, 1)
 
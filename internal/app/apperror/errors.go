@@ -5,11 +5,26 @@ import (
 )
 
 const (
-	NotFound       = "NOT_FOUND"
-	DatabaseError  = "DATABASE_ERROR"
-	InternalError  = "INTERNAL_ERROR"
-	DuplicateError = "DUPLICATE_ERROR"
-	BadRequest     = "BAD_REQUEST"
+	NotFound         = "NOT_FOUND"
+	DatabaseError    = "DATABASE_ERROR"
+	InternalError    = "INTERNAL_ERROR"
+	DuplicateError   = "DUPLICATE_ERROR"
+	BadRequest       = "BAD_REQUEST"
+	OutOfStock       = "OUT_OF_STOCK"
+	StorageError     = "STORAGE_ERROR"
+	TooLarge         = "PAYLOAD_TOO_LARGE"
+	UnsupportedMedia = "UNSUPPORTED_MEDIA_TYPE"
+	Unauthorized     = "UNAUTHORIZED"
+	Forbidden        = "FORBIDDEN"
+	ProductArchived  = "PRODUCT_ARCHIVED"
+	VersionMismatch  = "VERSION_MISMATCH"
+	InvalidStatus    = "INVALID_STATUS_TRANSITION"
+	RoundLimit       = "ROUND_LIMIT_REACHED"
+	StoreMismatch    = "STORE_MISMATCH"
+	InvalidPrice     = "INVALID_PRICE"
+	PriceTooHigh     = "PRICE_NOT_BELOW_LIST"
+	ProductNotFound  = "PRODUCT_NOT_FOUND"
+	VariantInUse     = "VARIANT_IN_USE"
 )
 
 type ProductError struct {
@@ -34,6 +49,66 @@ var (
 		Code:    NotFound,
 		Message: "store not found",
 	}
+	ErrProductAccessDenied = &ProductError{
+		Code:    Forbidden,
+		Message: "only the store owner can manage this product",
+	}
+	ErrProductVariantNotFound = &ProductError{
+		Code:    NotFound,
+		Message: "product variant not found",
+	}
+	ErrProductVariantInUse = &ProductError{
+		Code:    VariantInUse,
+		Message: "product variant is in offers or bundles and can't be deleted",
+	}
+	ErrProductImageNotFound = &ProductError{
+		Code:    NotFound,
+		Message: "product image not found",
+	}
+	ErrProductImageTooLarge = &ProductError{
+		Code:    TooLarge,
+		Message: "product image is too large",
+	}
+	ErrProductImageType = &ProductError{
+		Code:    UnsupportedMedia,
+		Message: "product image must be a JPEG, PNG or WebP file",
+	}
+	ErrAttributeNotFound = &ProductError{
+		Code:    NotFound,
+		Message: "attribute not found",
+	}
+	ErrAttributeAccessDenied = &ProductError{
+		Code:    Forbidden,
+		Message: "only administrators can manage category attributes",
+	}
+	ErrProductCurrency = &ProductError{
+		Code:    BadRequest,
+		Message: "currency must be a three letter ISO 4217 code",
+	}
+	ErrProductStatus = &ProductError{
+		Code:    BadRequest,
+		Message: "status must be draft, scheduled or published",
+	}
+	ErrProductStaffDenied = &ProductError{
+		Code:    Forbidden,
+		Message: "only the store staff can manage this product",
+	}
+	ErrProductUnpublishedDenied = &ProductError{
+		Code:    Forbidden,
+		Message: "only the store staff can list unpublished products",
+	}
+	ErrProductPublishAt = &ProductError{
+		Code:    BadRequest,
+		Message: "publish_at must be in the future and is only set for scheduled products",
+	}
+	ErrProductImageOrder = &ProductError{
+		Code:    BadRequest,
+		Message: "image order must list every product image exactly once",
+	}
+	ErrProductVersionMismatch = &ProductError{
+		Code:    VersionMismatch,
+		Message: "product was modified by someone else, reload it and try again",
+	}
 )
 
 type OfferError struct {
@@ -49,7 +124,369 @@ func (e *OfferError) Error() string {
 	return e.Message
 }
 
-var ErrOfferNotFound = &OfferError{
-	Code:    NotFound,
-	Message: "product not found",
+var (
+	ErrOfferNotFound = &OfferError{
+		Code:    NotFound,
+		Message: "offer not found",
+	}
+	ErrOfferVariantMismatch = &OfferError{
+		Code:    BadRequest,
+		Message: "variant does not belong to the offered product",
+	}
+	ErrOfferProductNotFound = &OfferError{
+		Code:    ProductNotFound,
+		Message: "offered product not found",
+	}
+	ErrOfferProductArchived = &OfferError{
+		Code:    ProductArchived,
+		Message: "offers can't be made on an archived product",
+	}
+	ErrOfferStoreNotFound = &OfferError{
+		Code:    NotFound,
+		Message: "store not found",
+	}
+	ErrOfferCurrency = &OfferError{
+		Code:    BadRequest,
+		Message: "currency must be a three letter ISO 4217 code",
+	}
+	ErrOfferStatus = &OfferError{
+		Code:    BadRequest,
+		Message: "unknown offer status",
+	}
+	ErrOfferTransition = &OfferError{
+		Code:    InvalidStatus,
+		Message: "offer can't move to this status from its current one",
+	}
+	ErrOfferCounterRequired = &OfferError{
+		Code:    BadRequest,
+		Message: "a counter-offer needs a price, make it through the counter endpoint",
+	}
+	ErrOfferCounterDenied = &OfferError{
+		Code:    Forbidden,
+		Message: "only the store staff can counter a pending offer and only the buyer a countered one",
+	}
+	ErrOfferStatusDenied = &OfferError{
+		Code:    Forbidden,
+		Message: "an offer is answered by the side it waits for, completed by the store staff and withdrawn by the buyer",
+	}
+	ErrOfferExpireManual = &OfferError{
+		Code:    BadRequest,
+		Message: "offers expire on their own once unanswered past their expiration",
+	}
+	ErrOfferRoundLimit = &OfferError{
+		Code:    RoundLimit,
+		Message: "offer has reached its maximum number of negotiation rounds",
+	}
+	ErrOfferPrice = &OfferError{
+		Code:    InvalidPrice,
+		Message: "price must be positive",
+	}
+	ErrOfferPriceTooHigh = &OfferError{
+		Code:    PriceTooHigh,
+		Message: "offered price must be below the current list price",
+	}
+	ErrOfferInboxDenied = &OfferError{
+		Code:    Forbidden,
+		Message: "only the store staff can see the offers made to the store",
+	}
+	ErrOfferSort = &OfferError{
+		Code:    BadRequest,
+		Message: "unknown offer order",
+	}
+	ErrOfferStoreMismatch = &OfferError{
+		Code:    StoreMismatch,
+		Message: "offered product is not sold by this store",
+	}
+	ErrOfferTarget = &OfferError{
+		Code:    BadRequest,
+		Message: "offer must be made on either a product or a bundle",
+	}
+	ErrOfferBundleNotFound = &OfferError{
+		Code:    NotFound,
+		Message: "bundle not found",
+	}
+	ErrOfferNotReserved = &OfferError{
+		Code:    InvalidStatus,
+		Message: "offer has no active stock reservation to complete",
+	}
+	ErrOfferOutOfStock = &OfferError{
+		Code:    OutOfStock,
+		Message: "not enough stock to reserve for this offer",
+	}
+)
+
+type CatalogError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *CatalogError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrCatalogImportNotFound = &CatalogError{
+		Code:    NotFound,
+		Message: "catalog import not found",
+	}
+	ErrCatalogAccessDenied = &CatalogError{
+		Code:    Forbidden,
+		Message: "only store members can manage the store catalog",
+	}
+	ErrCatalogFormat = &CatalogError{
+		Code:    UnsupportedMedia,
+		Message: "catalog file must be CSV or NDJSON",
+	}
+	ErrCatalogFileTooLarge = &CatalogError{
+		Code:    TooLarge,
+		Message: "catalog file is too large",
+	}
+)
+
+type CurrencyError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *CurrencyError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
 }
+
+var (
+	ErrCurrencyInvalid = &CurrencyError{
+		Code:    BadRequest,
+		Message: "currency must be a three letter ISO 4217 code",
+	}
+	ErrExchangeRateInvalid = &CurrencyError{
+		Code:    BadRequest,
+		Message: "exchange rate must be a positive number",
+	}
+	ErrCurrencyAccessDenied = &CurrencyError{
+		Code:    Forbidden,
+		Message: "only administrators can manage exchange rates",
+	}
+)
+
+type WatchlistError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *WatchlistError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrWatchlistItemNotFound = &WatchlistError{
+		Code:    NotFound,
+		Message: "watchlist item not found",
+	}
+	ErrWatchlistProductNotFound = &WatchlistError{
+		Code:    NotFound,
+		Message: "product not found",
+	}
+	ErrWatchlistStoreNotFound = &WatchlistError{
+		Code:    NotFound,
+		Message: "store not found",
+	}
+	ErrWatchlistItemType = &WatchlistError{
+		Code:    BadRequest,
+		Message: "watchlist item type must be product or store",
+	}
+)
+
+type AlertError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *AlertError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrAlertNotFound = &AlertError{
+		Code:    NotFound,
+		Message: "price alert not found",
+	}
+	ErrAlertProductNotFound = &AlertError{
+		Code:    NotFound,
+		Message: "product not found",
+	}
+	ErrAlertTargetPrice = &AlertError{
+		Code:    BadRequest,
+		Message: "target price must be positive",
+	}
+)
+
+type QuestionError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *QuestionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrQuestionNotFound = &QuestionError{
+		Code:    NotFound,
+		Message: "question not found",
+	}
+	ErrQuestionProductNotFound = &QuestionError{
+		Code:    NotFound,
+		Message: "product not found",
+	}
+	ErrQuestionAccessDenied = &QuestionError{
+		Code:    Forbidden,
+		Message: "only the store staff can answer questions about its products",
+	}
+	ErrQuestionModerationDenied = &QuestionError{
+		Code:    Forbidden,
+		Message: "only administrators can moderate questions",
+	}
+	ErrQuestionText = &QuestionError{
+		Code:    BadRequest,
+		Message: "text must not be empty or longer than 2000 characters",
+	}
+	ErrQuestionNotAnswered = &QuestionError{
+		Code:    BadRequest,
+		Message: "question has no answer to moderate",
+	}
+)
+
+type ReviewError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *ReviewError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrReviewNotFound = &ReviewError{
+		Code:    NotFound,
+		Message: "review not found",
+	}
+	ErrReviewProductNotFound = &ReviewError{
+		Code:    NotFound,
+		Message: "product not found",
+	}
+	ErrReviewNotVerified = &ReviewError{
+		Code:    Forbidden,
+		Message: "only buyers with an accepted offer on the product can review it",
+	}
+	ErrReviewAccessDenied = &ReviewError{
+		Code:    Forbidden,
+		Message: "only the author can change a review",
+	}
+	ErrReviewExists = &ReviewError{
+		Code:    DuplicateError,
+		Message: "product is already reviewed",
+	}
+	ErrReviewRating = &ReviewError{
+		Code:    BadRequest,
+		Message: "rating must be between 1 and 5",
+	}
+	ErrReviewText = &ReviewError{
+		Code:    BadRequest,
+		Message: "text must not be longer than 5000 characters",
+	}
+	ErrReviewSort = &ReviewError{
+		Code:    BadRequest,
+		Message: "sort must be newest, oldest, rating_high or rating_low",
+	}
+	ErrReviewPhotoNotFound = &ReviewError{
+		Code:    NotFound,
+		Message: "review photo not found",
+	}
+	ErrReviewPhotoLimit = &ReviewError{
+		Code:    BadRequest,
+		Message: "review already has 5 photos",
+	}
+	ErrReviewPhotoTooLarge = &ReviewError{
+		Code:    TooLarge,
+		Message: "review photo is too large",
+	}
+	ErrReviewPhotoType = &ReviewError{
+		Code:    UnsupportedMedia,
+		Message: "review photo must be a JPEG, PNG or WebP file",
+	}
+)
+
+type BundleError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *BundleError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrBundleNotFound = &BundleError{
+		Code:    NotFound,
+		Message: "bundle not found",
+	}
+	ErrBundleProductNotFound = &BundleError{
+		Code:    NotFound,
+		Message: "product not found",
+	}
+	ErrBundleVariantMismatch = &BundleError{
+		Code:    BadRequest,
+		Message: "variant does not belong to the product",
+	}
+	ErrBundleStoreMismatch = &BundleError{
+		Code:    BadRequest,
+		Message: "all bundle products must be sold by the same store",
+	}
+	ErrBundleAccessDenied = &BundleError{
+		Code:    Forbidden,
+		Message: "only the store staff can define store bundles",
+	}
+	ErrBundleName = &BundleError{
+		Code:    BadRequest,
+		Message: "name must not be empty or longer than 200 characters",
+	}
+	ErrBundleItems = &BundleError{
+		Code:    BadRequest,
+		Message: "bundle must hold at least two units of up to 20 products",
+	}
+	ErrBundleQuantity = &BundleError{
+		Code:    BadRequest,
+		Message: "quantity must be positive",
+	}
+	ErrBundleDuplicateItem = &BundleError{
+		Code:    BadRequest,
+		Message: "a product or variant can appear only once in a bundle",
+	}
+)
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -17,6 +19,33 @@ type Config struct {
 	BucketName    string
 	URL           string
 	SigningRegion string
+	BucketPublic  bool
+	PresignTTL    time.Duration
+	ImageMaxSize  int64
+
+	OfferTTL             time.Duration
+	OfferMaxRounds       int
+	OfferExpireInterval  time.Duration
+	OfferExpireBatchSize int
+
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
+	ReservationBatchSize     int
+
+	ImageProcessInterval  time.Duration
+	ImageProcessBatchSize int
+
+	ImportMaxSize   int64
+	ImportInterval  time.Duration
+	ImportBatchSize int
+
+	RelatedRefreshInterval  time.Duration
+	RelatedRefreshBatchSize int
+
+	PublishInterval  time.Duration
+	PublishBatchSize int
+
+	ExchangeRatesFile string
 }
 
 func LoadConfig() *Config {
@@ -33,6 +62,33 @@ func LoadConfig() *Config {
 		BucketName:    getEnv("BUCKET_NAME", "stawberry"),
 		URL:           getEnv("URL", "https://storage.yandexcloud.net"),
 		SigningRegion: getEnv("SIGNING_REGION", "ru-central1"),
+		BucketPublic:  getEnv("BUCKET_PUBLIC", "false") == "true",
+		PresignTTL:    getEnvDuration("PRESIGN_TTL", 15*time.Minute),
+		ImageMaxSize:  int64(getEnvInt("IMAGE_MAX_SIZE", 10<<20)),
+
+		OfferTTL:             getEnvDuration("OFFER_TTL", 24*time.Hour),
+		OfferMaxRounds:       getEnvInt("OFFER_MAX_ROUNDS", 5),
+		OfferExpireInterval:  getEnvDuration("OFFER_EXPIRE_INTERVAL", time.Minute),
+		OfferExpireBatchSize: getEnvInt("OFFER_EXPIRE_BATCH_SIZE", 100),
+
+		ReservationTTL:           getEnvDuration("RESERVATION_TTL", 48*time.Hour),
+		ReservationSweepInterval: getEnvDuration("RESERVATION_SWEEP_INTERVAL", time.Minute),
+		ReservationBatchSize:     getEnvInt("RESERVATION_BATCH_SIZE", 100),
+
+		ImageProcessInterval:  getEnvDuration("IMAGE_PROCESS_INTERVAL", 5*time.Second),
+		ImageProcessBatchSize: getEnvInt("IMAGE_PROCESS_BATCH_SIZE", 5),
+
+		ImportMaxSize:   int64(getEnvInt("IMPORT_MAX_SIZE", 50<<20)),
+		ImportInterval:  getEnvDuration("IMPORT_INTERVAL", 10*time.Second),
+		ImportBatchSize: getEnvInt("IMPORT_BATCH_SIZE", 1),
+
+		RelatedRefreshInterval:  getEnvDuration("RELATED_REFRESH_INTERVAL", time.Hour),
+		RelatedRefreshBatchSize: getEnvInt("RELATED_REFRESH_BATCH_SIZE", 50),
+
+		PublishInterval:  getEnvDuration("PUBLISH_INTERVAL", time.Minute),
+		PublishBatchSize: getEnvInt("PUBLISH_BATCH_SIZE", 100),
+
+		ExchangeRatesFile: getEnv("EXCHANGE_RATES_FILE", ""),
 	}
 }
 
@@ -43,6 +99,20 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func (c *Config) GetDBConnString() string {
 	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type PriceAlert struct {
+	ID           uint        `json:"id"`
+	ProductID    uint        `json:"product_id"`
+	ProductName  string      `json:"product_name"`
+	TargetPrice  money.Money `json:"target_price"`
+	CurrentPrice money.Money `json:"current_price"`
+	Currency     string      `json:"currency"`
+	Armed        bool        `json:"armed"`
+	TriggeredAt  *time.Time  `json:"triggered_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type Bundle struct {
+	ID        uint         `json:"id"`
+	StoreID   uint         `json:"store_id"`
+	UserID    *uint        `json:"user_id,omitempty"`
+	Name      string       `json:"name"`
+	Price     money.Money  `json:"price"`
+	Currency  string       `json:"currency"`
+	Items     []BundleItem `json:"items"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type BundleItem struct {
+	ProductID uint        `json:"product_id"`
+	VariantID *uint       `json:"variant_id,omitempty"`
+	Name      string      `json:"name"`
+	Quantity  int         `json:"quantity"`
+	UnitPrice money.Money `json:"unit_price"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+type CatalogImport struct {
+	ID            uint       `json:"id"`
+	StoreID       uint       `json:"store_id"`
+	UserID        *uint      `json:"user_id,omitempty"`
+	Format        string     `json:"format"`
+	ObjectKey     string     `json:"-"`
+	Status        string     `json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	SucceededRows int        `json:"succeeded_rows"`
+	FailedRows    int        `json:"failed_rows"`
+	Error         *string    `json:"error,omitempty"`
+	Attempts      int        `json:"-"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type CatalogImportError struct {
+	Line        int    `json:"line"`
+	ExternalSKU string `json:"external_sku"`
+	Message     string `json:"message"`
+}
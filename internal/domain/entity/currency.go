@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// ExchangeRate is the price of one unit of Currency in the base currency.
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
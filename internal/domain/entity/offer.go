@@ -1,15 +1,35 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
 
 type Offer struct {
-	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	StoreID   uint      `json:"store_id"`
-	Price     float64   `json:"price"`
-	Status    string    `json:"status"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint         `json:"id"`
+	UserID    uint         `json:"user_id"`
+	ProductID *uint        `json:"product_id,omitempty"`
+	VariantID *uint        `json:"variant_id,omitempty"`
+	BundleID  *uint        `json:"bundle_id,omitempty"`
+	StoreID   uint         `json:"store_id"`
+	Price     money.Money  `json:"price"`
+	Currency  string       `json:"currency"`
+	Status    string       `json:"status"`
+	MaxRounds int          `json:"max_rounds"`
+	Rounds    []OfferRound `json:"rounds,omitempty"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// OfferRound is a step of the negotiation thread of an offer, in order.
+type OfferRound struct {
+	ID        uint        `json:"id"`
+	OfferID   uint        `json:"offer_id"`
+	Round     int         `json:"round"`
+	UserID    *uint       `json:"user_id,omitempty"`
+	Side      string      `json:"side"`
+	Price     money.Money `json:"price"`
+	CreatedAt time.Time   `json:"created_at"`
 }
@@ -1,15 +1,104 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
 
 type Product struct {
-	ID          uint      `json:"id"`
-	StoreID     uint      `json:"store_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Category    string    `json:"category"`
-	InStock     bool      `json:"in_stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uint                   `json:"id"`
+	StoreID         uint                   `json:"store_id"`
+	ExternalSKU     *string                `json:"external_sku,omitempty"`
+	Slug            string                 `json:"slug"`
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	Price           money.Money            `json:"price"`
+	Currency        string                 `json:"currency"`
+	DisplayPrice    *money.Money           `json:"display_price,omitempty"`
+	DisplayCurrency string                 `json:"display_currency,omitempty"`
+	LowestPrice30d  money.Money            `json:"lowest_price_30d"`
+	Category        string                 `json:"category"`
+	Quantity        int                    `json:"quantity"`
+	InStock         bool                   `json:"in_stock"`
+	Status          string                 `json:"status"`
+	PublishAt       *time.Time             `json:"publish_at,omitempty"`
+	WatcherCount    int                    `json:"watcher_count"`
+	RatingAverage   float64                `json:"rating_average"`
+	ReviewCount     int                    `json:"review_count"`
+	ArchivedAt      *time.Time             `json:"archived_at,omitempty"`
+	Version         int                    `json:"version"`
+	Attributes      map[string]interface{} `json:"attributes,omitempty"`
+	Variants        []ProductVariant       `json:"variants,omitempty"`
+	Images          []ProductImage         `json:"images,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+type ProductVariant struct {
+	ID         uint              `json:"id"`
+	ProductID  uint              `json:"product_id"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price"`
+	Stock      int               `json:"stock"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type ProductImage struct {
+	ID          uint                    `json:"id"`
+	ProductID   uint                    `json:"product_id"`
+	ObjectKey   string                  `json:"-"`
+	URL         string                  `json:"url"`
+	ContentType string                  `json:"content_type"`
+	Size        int64                   `json:"size"`
+	Position    int                     `json:"position"`
+	IsPrimary   bool                    `json:"is_primary"`
+	Status      string                  `json:"status"`
+	Attempts    int                     `json:"-"`
+	Renditions  []ProductImageRendition `json:"renditions,omitempty"`
+	CreatedAt   time.Time               `json:"created_at"`
+}
+
+type ProductImageRendition struct {
+	Name        string `json:"name"`
+	ObjectKey   string `json:"-"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+}
+
+type PriceChange struct {
+	ID        uint         `json:"id"`
+	ProductID uint         `json:"product_id"`
+	OldPrice  *money.Money `json:"old_price"`
+	NewPrice  money.Money  `json:"new_price"`
+	ChangedBy *uint        `json:"changed_by,omitempty"`
+	ChangedAt time.Time    `json:"changed_at"`
+}
+
+type AttributeDefinition struct {
+	ID        uint      `json:"id"`
+	Category  string    `json:"category"`
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options,omitempty"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Facet counts the products of a result set per value of an attribute.
+type Facet struct {
+	Attribute string       `json:"attribute"`
+	Name      string       `json:"name"`
+	Values    []FacetValue `json:"values"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
 }
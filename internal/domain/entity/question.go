@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type ProductQuestion struct {
+	ID         uint       `json:"id"`
+	ProductID  uint       `json:"product_id"`
+	StoreID    uint       `json:"store_id"`
+	UserID     uint       `json:"user_id"`
+	Question   string     `json:"question"`
+	Answer     *string    `json:"answer,omitempty"`
+	AnsweredBy *uint      `json:"answered_by,omitempty"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	HiddenAt   *time.Time `json:"hidden_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type ProductReview struct {
+	ID        uint          `json:"id"`
+	ProductID uint          `json:"product_id"`
+	UserID    uint          `json:"user_id"`
+	OfferID   uint          `json:"offer_id"`
+	Rating    int           `json:"rating"`
+	Text      string        `json:"text"`
+	Photos    []ReviewPhoto `json:"photos"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type ReviewPhoto struct {
+	ID          uint      `json:"id"`
+	ReviewID    uint      `json:"review_id"`
+	ObjectKey   string    `json:"-"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+}
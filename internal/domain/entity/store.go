@@ -7,6 +7,7 @@ type Store struct {
 	ID          uint
 	Name        string
 	Description string
+	Currency    string
 	Products    []Product
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type WatchlistItem struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	Product   *WatchedProduct `json:"product,omitempty"`
+	Store     *WatchedStore   `json:"store,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// WatchedProduct is the current state of a watched product.
+type WatchedProduct struct {
+	ID         uint        `json:"id"`
+	StoreID    uint        `json:"store_id"`
+	Name       string      `json:"name"`
+	Price      money.Money `json:"price"`
+	Currency   string      `json:"currency"`
+	Quantity   int         `json:"quantity"`
+	InStock    bool        `json:"in_stock"`
+	ArchivedAt *time.Time  `json:"archived_at,omitempty"`
+}
+
+type WatchedStore struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Currency    string `json:"currency"`
+}
@@ -0,0 +1,43 @@
+package alert
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+)
+
+type Repository interface {
+	UpsertPriceAlert(alert PriceAlert) (entity.PriceAlert, error)
+	RearmPriceAlert(userID, alertID uint) (entity.PriceAlert, error)
+	DeletePriceAlert(userID, alertID uint) error
+	SelectUserPriceAlerts(userID uint, offset, limit int) ([]entity.PriceAlert, int, error)
+}
+
+type alertService struct {
+	alertRepository Repository
+}
+
+func NewAlertService(alertRepo Repository) *alertService {
+	return &alertService{alertRepository: alertRepo}
+}
+
+// SetPriceAlert subscribes the user to price drops of the product. Setting
+// the alert again replaces the target and re-arms it.
+func (as *alertService) SetPriceAlert(alert PriceAlert) (entity.PriceAlert, error) {
+	if !alert.TargetPrice.IsPositive() {
+		return entity.PriceAlert{}, apperror.ErrAlertTargetPrice
+	}
+	return as.alertRepository.UpsertPriceAlert(alert)
+}
+
+// RearmPriceAlert lets a triggered alert fire again on the next price drop.
+func (as *alertService) RearmPriceAlert(userID, alertID uint) (entity.PriceAlert, error) {
+	return as.alertRepository.RearmPriceAlert(userID, alertID)
+}
+
+func (as *alertService) DeletePriceAlert(userID, alertID uint) error {
+	return as.alertRepository.DeletePriceAlert(userID, alertID)
+}
+
+func (as *alertService) GetPriceAlerts(userID uint, offset, limit int) ([]entity.PriceAlert, int, error) {
+	return as.alertRepository.SelectUserPriceAlerts(userID, offset, limit)
+}
@@ -0,0 +1,11 @@
+package alert
+
+import "github.com/PosokhovVadim/stawberry/pkg/money"
+
+// PriceAlert asks to notify the user once the product price drops to
+// TargetPrice or below. The target is in the product currency.
+type PriceAlert struct {
+	UserID      uint
+	ProductID   uint
+	TargetPrice money.Money
+}
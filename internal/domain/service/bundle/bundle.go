@@ -0,0 +1,247 @@
+package bundle
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+)
+
+type Repository interface {
+	InsertBundle(bundle Bundle) (uint, error)
+	GetBundle(bundleID uint) (entity.Bundle, error)
+	SelectStoreBundles(storeID uint, offset, limit int) ([]entity.Bundle, int, error)
+}
+
+type StoreMembership interface {
+	IsStoreMember(storeID, userID uint, roles ...string) (bool, error)
+}
+
+type StoreDirectory interface {
+	GetStoreCurrency(storeID uint) (string, error)
+}
+
+type RateProvider interface {
+	GetRates() (currency.Rates, error)
+}
+
+type bundleService struct {
+	bundleRepository Repository
+	storeMembership  StoreMembership
+	storeDirectory   StoreDirectory
+	rateProvider     RateProvider
+}
+
+func NewBundleService(
+	bundleRepo Repository,
+	storeMembership StoreMembership,
+	storeDirectory StoreDirectory,
+	rateProvider RateProvider,
+) *bundleService {
+	return &bundleService{
+		bundleRepository: bundleRepo,
+		storeMembership:  storeMembership,
+		storeDirectory:   storeDirectory,
+		rateProvider:     rateProvider,
+	}
+}
+
+// CreateStoreBundle defines a bundle every buyer can make offers on. Only the
+// staff of the store can define its bundles.
+func (bs *bundleService) CreateStoreBundle(userID uint, bundle Bundle) (entity.Bundle, error) {
+	if err := normalize(&bundle); err != nil {
+		return entity.Bundle{}, err
+	}
+
+	if err := bs.checkStoreMember(bundle.StoreID, userID); err != nil {
+		return entity.Bundle{}, err
+	}
+	bundle.UserID = nil
+
+	return bs.create(bundle)
+}
+
+// CreateBuyerBundle assembles a private bundle the buyer can make an offer
+// on. The store is the one selling the products.
+func (bs *bundleService) CreateBuyerBundle(userID uint, bundle Bundle) (entity.Bundle, error) {
+	if err := normalize(&bundle); err != nil {
+		return entity.Bundle{}, err
+	}
+	bundle.StoreID = 0
+	bundle.UserID = &userID
+
+	return bs.create(bundle)
+}
+
+// GetBundle returns a bundle with its price. Buyer bundles are only shown to
+// the buyer who assembled them and to the store staff.
+func (bs *bundleService) GetBundle(bundleID uint, actorID *uint) (entity.Bundle, error) {
+	b, err := bs.bundleRepository.GetBundle(bundleID)
+	if err != nil {
+		return entity.Bundle{}, err
+	}
+
+	if b.UserID != nil {
+		if err := bs.checkBuyerBundleAccess(b, actorID); err != nil {
+			return entity.Bundle{}, err
+		}
+	}
+
+	return bs.priced(b)
+}
+
+// GetStoreBundles lists the bundles defined by a store, newest first.
+func (bs *bundleService) GetStoreBundles(storeID uint, offset, limit int) ([]entity.Bundle, int, error) {
+	bundles, total, err := bs.bundleRepository.SelectStoreBundles(storeID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := bs.setPrices(bundles); err != nil {
+		return nil, 0, err
+	}
+	return bundles, total, nil
+}
+
+func (bs *bundleService) create(bundle Bundle) (entity.Bundle, error) {
+	id, err := bs.bundleRepository.InsertBundle(bundle)
+	if err != nil {
+		return entity.Bundle{}, err
+	}
+
+	b, err := bs.bundleRepository.GetBundle(id)
+	if err != nil {
+		return entity.Bundle{}, err
+	}
+
+	return bs.priced(b)
+}
+
+func (bs *bundleService) priced(b entity.Bundle) (entity.Bundle, error) {
+	bundles := []entity.Bundle{b}
+	if err := bs.setPrices(bundles); err != nil {
+		return entity.Bundle{}, err
+	}
+	return bundles[0], nil
+}
+
+// setPrices sets the price of every bundle: the sum of its items in the store
+// currency, the one offers on the bundle are settled in.
+func (bs *bundleService) setPrices(bundles []entity.Bundle) error {
+	var rates currency.Rates
+	for i := range bundles {
+		storeCurrency, err := bs.storeDirectory.GetStoreCurrency(bundles[i].StoreID)
+		if err != nil {
+			return &apperror.BundleError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch store currency",
+				Err:     err,
+			}
+		}
+
+		bundles[i].Currency = storeCurrency
+		bundles[i].Price = bundles[i].Price.WithCurrency(storeCurrency)
+		for _, item := range bundles[i].Items {
+			unitPrice := item.UnitPrice
+			if unitPrice.Currency != storeCurrency {
+				if rates == nil {
+					if rates, err = bs.rateProvider.GetRates(); err != nil {
+						return err
+					}
+				}
+				if unitPrice, err = rates.Convert(unitPrice, storeCurrency); err != nil {
+					return &apperror.BundleError{
+						Code:    apperror.InternalError,
+						Message: "no exchange rate for a bundle product currency",
+						Err:     err,
+					}
+				}
+			}
+
+			bundles[i].Price = bundles[i].Price.Add(unitPrice.Mul(big.NewRat(int64(item.Quantity), 1)))
+		}
+	}
+
+	return nil
+}
+
+// checkBuyerBundleAccess hides a buyer bundle from everyone but its buyer and
+// the store staff.
+func (bs *bundleService) checkBuyerBundleAccess(b entity.Bundle, actorID *uint) error {
+	if actorID == nil {
+		return apperror.ErrBundleNotFound
+	}
+	if *actorID == *b.UserID {
+		return nil
+	}
+
+	err := bs.checkStoreMember(b.StoreID, *actorID)
+	if errors.Is(err, apperror.ErrBundleAccessDenied) {
+		return apperror.ErrBundleNotFound
+	}
+	return err
+}
+
+func (bs *bundleService) checkStoreMember(storeID, userID uint) error {
+	isMember, err := bs.storeMembership.IsStoreMember(storeID, userID, store.RoleOwner, store.RoleStaff)
+	if err != nil {
+		return &apperror.BundleError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store membership",
+			Err:     err,
+		}
+	}
+	if !isMember {
+		return apperror.ErrBundleAccessDenied
+	}
+	return nil
+}
+
+// normalize validates the name and items of a new bundle. An item without a
+// quantity holds a single unit.
+func normalize(bundle *Bundle) error {
+	bundle.Name = strings.TrimSpace(bundle.Name)
+	if bundle.Name == "" || utf8.RuneCountInString(bundle.Name) > MaxNameLength {
+		return apperror.ErrBundleName
+	}
+
+	if len(bundle.Items) == 0 || len(bundle.Items) > MaxItems {
+		return apperror.ErrBundleItems
+	}
+
+	type key struct {
+		productID uint
+		variantID uint
+	}
+	seen := make(map[key]bool, len(bundle.Items))
+	units := 0
+	for i := range bundle.Items {
+		item := &bundle.Items[i]
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.Quantity < 0 {
+			return apperror.ErrBundleQuantity
+		}
+
+		k := key{productID: item.ProductID}
+		if item.VariantID != nil {
+			k.variantID = *item.VariantID
+		}
+		if seen[k] {
+			return apperror.ErrBundleDuplicateItem
+		}
+		seen[k] = true
+		units += item.Quantity
+	}
+
+	if units < 2 {
+		return apperror.ErrBundleItems
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package bundle
+
+const (
+	// MaxNameLength is the longest bundle name in characters.
+	MaxNameLength = 200
+	// MaxItems is the largest number of distinct products in a bundle.
+	MaxItems = 20
+)
+
+// Bundle is a set of products sold together by one store. Store bundles are
+// defined by the store staff, buyer bundles are assembled by UserID.
+type Bundle struct {
+	StoreID uint
+	UserID  *uint
+	Name    string
+	Items   []Item
+}
+
+type Item struct {
+	ProductID uint
+	VariantID *uint
+	Quantity  int
+}
@@ -0,0 +1,148 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+)
+
+type Repository interface {
+	InsertImport(catalogImport Import) (uint, error)
+	GetImport(storeID, importID uint) (entity.CatalogImport, error)
+	ClaimPendingImports(limit int, staleAfter time.Duration) ([]entity.CatalogImport, error)
+	ResetImportProgress(importID uint, total int) error
+	UpsertCatalogRows(storeID uint, rows []Row, actorID *uint) ([]RowError, error)
+	UpdateImportProgress(importID uint, progress Progress, rowErrors []RowError) error
+	SetImportStatus(importID uint, status string, errMsg *string) error
+	SelectImportErrors(importID uint) ([]entity.CatalogImportError, error)
+	StreamStoreProducts(
+		ctx context.Context,
+		storeID uint,
+		filter product.ProductFilter,
+		fn func(entity.Product) error,
+	) error
+}
+
+type StoreMembership interface {
+	IsStoreMember(storeID, userID uint, roles ...string) (bool, error)
+}
+
+type ObjectStorage interface {
+	UploadFile(ctx context.Context, objectKey, contentType string, file io.Reader, size int64) error
+	DownloadFile(ctx context.Context, objectKey string) ([]byte, error)
+	DeleteFile(ctx context.Context, objectKey string) error
+}
+
+type catalogService struct {
+	catalogRepository Repository
+	storeMembership   StoreMembership
+	storage           ObjectStorage
+	importMaxSize     int64
+}
+
+func NewCatalogService(
+	catalogRepo Repository,
+	storeMembership StoreMembership,
+	storage ObjectStorage,
+	importMaxSize int64,
+) *catalogService {
+	return &catalogService{
+		catalogRepository: catalogRepo,
+		storeMembership:   storeMembership,
+		storage:           storage,
+		importMaxSize:     importMaxSize,
+	}
+}
+
+// ImportObjectKey returns the storage key of an uploaded catalog file:
+// stores/<store id>/imports/<unix nano>.<format>.
+func ImportObjectKey(storeID uint, uploadedAt time.Time, format string) string {
+	return fmt.Sprintf("stores/%d/imports/%d.%s", storeID, uploadedAt.UnixNano(), format)
+}
+
+// StartImport stores the uploaded catalog file and queues it for the
+// background importer.
+func (cs *catalogService) StartImport(
+	ctx context.Context,
+	storeID, userID uint,
+	format string,
+	file io.Reader,
+	size int64,
+) (entity.CatalogImport, error) {
+	if format != FormatCSV && format != FormatNDJSON {
+		return entity.CatalogImport{}, apperror.ErrCatalogFormat
+	}
+	if size > cs.importMaxSize {
+		return entity.CatalogImport{}, apperror.ErrCatalogFileTooLarge
+	}
+	if err := cs.checkStoreMember(storeID, userID); err != nil {
+		return entity.CatalogImport{}, err
+	}
+
+	now := time.Now()
+	objectKey := ImportObjectKey(storeID, now, format)
+	if err := cs.storage.UploadFile(ctx, objectKey, contentTypes[format], file, size); err != nil {
+		return entity.CatalogImport{}, &apperror.CatalogError{
+			Code:    apperror.StorageError,
+			Message: "failed to upload catalog file",
+			Err:     err,
+		}
+	}
+
+	importID, err := cs.catalogRepository.InsertImport(Import{
+		StoreID:   storeID,
+		UserID:    &userID,
+		Format:    format,
+		ObjectKey: objectKey,
+		Status:    ImportPending,
+		CreatedAt: now,
+	})
+	if err != nil {
+		if delErr := cs.storage.DeleteFile(ctx, objectKey); delErr != nil {
+			log.Printf("Failed to delete catalog file %s: %v", objectKey, delErr)
+		}
+		return entity.CatalogImport{}, err
+	}
+
+	return cs.catalogRepository.GetImport(storeID, importID)
+}
+
+func (cs *catalogService) GetImport(storeID, importID, userID uint) (entity.CatalogImport, error) {
+	if err := cs.checkStoreMember(storeID, userID); err != nil {
+		return entity.CatalogImport{}, err
+	}
+	return cs.catalogRepository.GetImport(storeID, importID)
+}
+
+// GetImportErrors returns the rejected rows of an import ordered by line.
+func (cs *catalogService) GetImportErrors(storeID, importID, userID uint) ([]entity.CatalogImportError, error) {
+	if _, err := cs.GetImport(storeID, importID, userID); err != nil {
+		return nil, err
+	}
+	return cs.catalogRepository.SelectImportErrors(importID)
+}
+
+// checkStoreMember returns ErrCatalogAccessDenied unless the user is an owner
+// or staff member of the store.
+func (cs *catalogService) checkStoreMember(storeID, userID uint) error {
+	isMember, err := cs.storeMembership.IsStoreMember(storeID, userID, store.RoleOwner, store.RoleStaff)
+	if err != nil {
+		return &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store membership",
+			Err:     err,
+		}
+	}
+	if !isMember {
+		return apperror.ErrCatalogAccessDenied
+	}
+
+	return nil
+}
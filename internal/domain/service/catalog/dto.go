@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+const (
+	ImportPending    = "pending"
+	ImportProcessing = "processing"
+	ImportCompleted  = "completed"
+	ImportFailed     = "failed"
+)
+
+type Import struct {
+	ID        uint      `json:"id"`
+	StoreID   uint      `json:"store_id"`
+	UserID    *uint     `json:"user_id,omitempty"`
+	Format    string    `json:"format"`
+	ObjectKey string    `json:"object_key"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Row is a validated catalog row, products are matched by their store
+// specific ExternalSKU.
+type Row struct {
+	Line        int         `json:"line"`
+	ExternalSKU string      `json:"external_sku"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	Currency    string      `json:"currency,omitempty"`
+	Category    string      `json:"category"`
+	Quantity    int         `json:"quantity"`
+}
+
+type RowError struct {
+	Line        int    `json:"line"`
+	ExternalSKU string `json:"external_sku"`
+	Message     string `json:"message"`
+}
+
+type Progress struct {
+	Processed int
+	Succeeded int
+	Failed    int
+}
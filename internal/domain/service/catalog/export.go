@@ -0,0 +1,138 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+// exportFlushInterval is how many rows are written between flushes to the client
+const exportFlushInterval = 500
+
+// exportColumns are the CSV export columns, the file can be imported back as is
+var exportColumns = []string{
+	"id", "external_sku", "name", "description", "price", "currency", "category", "quantity", "in_stock",
+	"created_at", "updated_at",
+}
+
+type exportRecord struct {
+	ID          uint        `json:"id"`
+	ExternalSKU string      `json:"external_sku"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	Currency    string      `json:"currency"`
+	Category    string      `json:"category"`
+	Quantity    int         `json:"quantity"`
+	InStock     bool        `json:"in_stock"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// ContentType returns the MIME type of a catalog file format.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// ExportCatalog streams the store products matching the filter to w in the
+// given format. Nothing is written to w if the request is rejected.
+func (cs *catalogService) ExportCatalog(
+	ctx context.Context,
+	storeID, userID uint,
+	format string,
+	filter product.ProductFilter,
+	w io.Writer,
+) error {
+	if format != FormatCSV && format != FormatNDJSON {
+		return apperror.ErrCatalogFormat
+	}
+	if err := cs.checkStoreMember(storeID, userID); err != nil {
+		return err
+	}
+
+	var encode func(exportRecord) error
+	var flushBuffer func() error
+	if format == FormatNDJSON {
+		encoder := json.NewEncoder(w)
+		encode = func(r exportRecord) error { return encoder.Encode(r) }
+		flushBuffer = func() error { return nil }
+	} else {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportColumns); err != nil {
+			return err
+		}
+		encode = func(r exportRecord) error { return writer.Write(csvRecord(r)) }
+		flushBuffer = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	// Rows are pushed to the client periodically so large exports start downloading right away
+	flusher, _ := w.(interface{ Flush() })
+	var written int
+	err := cs.catalogRepository.StreamStoreProducts(ctx, storeID, filter, func(p entity.Product) error {
+		if err := encode(newExportRecord(p)); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushInterval != 0 {
+			return nil
+		}
+		if err := flushBuffer(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flushBuffer()
+}
+
+func newExportRecord(p entity.Product) exportRecord {
+	record := exportRecord{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		Currency:    p.Currency,
+		Category:    p.Category,
+		Quantity:    p.Quantity,
+		InStock:     p.InStock,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+	if p.ExternalSKU != nil {
+		record.ExternalSKU = *p.ExternalSKU
+	}
+	return record
+}
+
+func csvRecord(r exportRecord) []string {
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		r.ExternalSKU,
+		r.Name,
+		r.Description,
+		r.Price.Decimal(),
+		r.Currency,
+		r.Category,
+		strconv.Itoa(r.Quantity),
+		strconv.FormatBool(r.InStock),
+		r.CreatedAt.Format(time.RFC3339),
+		r.UpdatedAt.Format(time.RFC3339),
+	}
+}
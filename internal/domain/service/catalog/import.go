@@ -0,0 +1,305 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+const (
+	// maxImportAttempts is how many times an import is tried before it is marked failed
+	maxImportAttempts = 3
+	// importClaimTimeout is how long an import may stay in processing before it is claimed again
+	importClaimTimeout = 30 * time.Minute
+	// importChunkSize is how many rows are upserted between progress updates
+	importChunkSize = 200
+	// maxNDJSONLine bounds the length of a single NDJSON record
+	maxNDJSONLine = 1 << 20
+)
+
+var contentTypes = map[string]string{
+	FormatCSV:    "text/csv",
+	FormatNDJSON: "application/x-ndjson",
+}
+
+// csvColumns are the recognised CSV header names, external_sku, name and price are required
+var csvColumns = []string{"external_sku", "name", "description", "price", "currency", "category", "quantity"}
+
+// utf8BOM is written by spreadsheet software at the start of CSV exports
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// errCatalogFile is returned when the file as a whole can't be read, as
+// opposed to invalid rows which are reported individually.
+var errCatalogFile = errors.New("invalid catalog file")
+
+// ProcessPendingImports claims up to limit queued imports and applies them to
+// the store catalogs. It returns the number of claimed imports.
+func (cs *catalogService) ProcessPendingImports(ctx context.Context, limit int) (int, error) {
+	imports, err := cs.catalogRepository.ClaimPendingImports(limit, importClaimTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, catalogImport := range imports {
+		err := cs.processImport(ctx, catalogImport)
+		if err == nil {
+			continue
+		}
+
+		log.Printf("Failed to process catalog import %d: %v", catalogImport.ID, err)
+		cs.retryImport(catalogImport, err)
+	}
+
+	return len(imports), nil
+}
+
+func (cs *catalogService) processImport(ctx context.Context, catalogImport entity.CatalogImport) error {
+	data, err := cs.storage.DownloadFile(ctx, catalogImport.ObjectKey)
+	if err != nil {
+		return err
+	}
+
+	rows, rowErrors, err := parseCatalog(catalogImport.Format, data)
+	if err != nil {
+		msg := err.Error()
+		return cs.catalogRepository.SetImportStatus(catalogImport.ID, ImportFailed, &msg)
+	}
+
+	// A re-claimed import starts over, upserts are idempotent
+	total := len(rows) + len(rowErrors)
+	if err := cs.catalogRepository.ResetImportProgress(catalogImport.ID, total); err != nil {
+		return err
+	}
+
+	progress := Progress{
+		Processed: len(rowErrors),
+		Failed:    len(rowErrors),
+	}
+	if err := cs.catalogRepository.UpdateImportProgress(catalogImport.ID, progress, rowErrors); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(rows); start += importChunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunk := rows[start:min(start+importChunkSize, len(rows))]
+		failed, err := cs.catalogRepository.UpsertCatalogRows(catalogImport.StoreID, chunk, catalogImport.UserID)
+		if err != nil {
+			return err
+		}
+
+		progress.Processed += len(chunk)
+		progress.Succeeded += len(chunk) - len(failed)
+		progress.Failed += len(failed)
+		if err := cs.catalogRepository.UpdateImportProgress(catalogImport.ID, progress, failed); err != nil {
+			return err
+		}
+	}
+
+	return cs.catalogRepository.SetImportStatus(catalogImport.ID, ImportCompleted, nil)
+}
+
+// retryImport puts the import back in the queue or gives up after maxImportAttempts.
+func (cs *catalogService) retryImport(catalogImport entity.CatalogImport, cause error) {
+	status := ImportPending
+	var errMsg *string
+	if catalogImport.Attempts >= maxImportAttempts {
+		status = ImportFailed
+		msg := cause.Error()
+		errMsg = &msg
+	}
+
+	if err := cs.catalogRepository.SetImportStatus(catalogImport.ID, status, errMsg); err != nil {
+		log.Printf("Failed to update catalog import %d: %v", catalogImport.ID, err)
+	}
+}
+
+// parseCatalog reads every row of the file. Rows that fail validation are
+// returned as row errors, an unreadable file is returned as an error.
+func parseCatalog(format string, data []byte) ([]Row, []RowError, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(data)
+	case FormatNDJSON:
+		return parseNDJSON(data)
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported format %q", errCatalogFile, format)
+	}
+}
+
+func parseCSV(data []byte) ([]Row, []RowError, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to read CSV header: %v", errCatalogFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"external_sku", "name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing %q column", errCatalogFile, required)
+		}
+	}
+
+	var (
+		rows      []Row
+		rowErrors []RowError
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, RowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", errCatalogFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		fields := make(map[string]string, len(csvColumns))
+		for _, name := range csvColumns {
+			if i, ok := columns[name]; ok && i < len(record) {
+				fields[name] = strings.TrimSpace(record[i])
+			}
+		}
+
+		row, err := csvRow(line, fields)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, ExternalSKU: fields["external_sku"], Message: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func csvRow(line int, fields map[string]string) (Row, error) {
+	row := Row{
+		Line:        line,
+		ExternalSKU: fields["external_sku"],
+		Name:        fields["name"],
+		Description: fields["description"],
+		Currency:    strings.ToUpper(fields["currency"]),
+		Category:    fields["category"],
+	}
+	if row.Currency != "" && !money.ValidCurrency(row.Currency) {
+		return Row{}, fmt.Errorf("invalid currency %q", fields["currency"])
+	}
+
+	price, err := money.Parse(fields["price"], row.Currency)
+	if err != nil {
+		return Row{}, fmt.Errorf("invalid price %q", fields["price"])
+	}
+	row.Price = price
+
+	if fields["quantity"] != "" {
+		quantity, err := strconv.Atoi(fields["quantity"])
+		if err != nil {
+			return Row{}, fmt.Errorf("invalid quantity %q", fields["quantity"])
+		}
+		row.Quantity = quantity
+	}
+
+	return row, validateRow(row)
+}
+
+func parseNDJSON(data []byte) ([]Row, []RowError, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+
+	var (
+		rows      []Row
+		rowErrors []RowError
+	)
+	for line := 1; scanner.Scan(); line++ {
+		record := bytes.TrimSpace(scanner.Bytes())
+		if len(record) == 0 {
+			continue
+		}
+
+		var fields struct {
+			ExternalSKU string      `json:"external_sku"`
+			Name        string      `json:"name"`
+			Description string      `json:"description"`
+			Price       money.Money `json:"price"`
+			Currency    string      `json:"currency"`
+			Category    string      `json:"category"`
+			Quantity    int         `json:"quantity"`
+
+			// Read-only columns of an export, accepted so that an export imports back
+			ID        json.RawMessage `json:"id"`
+			InStock   json.RawMessage `json:"in_stock"`
+			CreatedAt json.RawMessage `json:"created_at"`
+			UpdatedAt json.RawMessage `json:"updated_at"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(record))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&fields); err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: "invalid JSON: " + err.Error()})
+			continue
+		}
+
+		row := Row{
+			Line:        line,
+			ExternalSKU: strings.TrimSpace(fields.ExternalSKU),
+			Name:        strings.TrimSpace(fields.Name),
+			Description: fields.Description,
+			Price:       fields.Price,
+			Currency:    strings.ToUpper(strings.TrimSpace(fields.Currency)),
+			Category:    strings.TrimSpace(fields.Category),
+			Quantity:    fields.Quantity,
+		}
+		if err := validateRow(row); err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, ExternalSKU: row.ExternalSKU, Message: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errCatalogFile, err)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func validateRow(row Row) error {
+	switch {
+	case row.ExternalSKU == "":
+		return errors.New("external_sku is required")
+	case row.Name == "":
+		return errors.New("name is required")
+	case row.Currency != "" && !money.ValidCurrency(row.Currency):
+		return errors.New("currency must be a three letter ISO 4217 code")
+	case !row.Price.IsPositive():
+		return errors.New("price must be positive")
+	case row.Quantity < 0:
+		return errors.New("quantity can't be negative")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package currency
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type Repository interface {
+	SelectExchangeRates() ([]entity.ExchangeRate, error)
+	UpsertExchangeRates(rates []Rate) error
+}
+
+type AdminChecker interface {
+	IsAdmin(userID uint) (bool, error)
+}
+
+type currencyService struct {
+	currencyRepository Repository
+	adminChecker       AdminChecker
+}
+
+func NewCurrencyService(currencyRepo Repository, adminChecker AdminChecker) *currencyService {
+	return &currencyService{
+		currencyRepository: currencyRepo,
+		adminChecker:       adminChecker,
+	}
+}
+
+// GetRates returns the current exchange rates for conversions.
+func (cs *currencyService) GetRates() (Rates, error) {
+	exchangeRates, err := cs.currencyRepository.SelectExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(Rates, len(exchangeRates))
+	for _, r := range exchangeRates {
+		rate, ok := new(big.Rat).SetString(r.Rate)
+		if !ok {
+			return nil, &apperror.CurrencyError{
+				Code:    apperror.InternalError,
+				Message: "invalid stored exchange rate",
+				Err:     fmt.Errorf("%s: %q", r.Currency, r.Rate),
+			}
+		}
+		rates[r.Currency] = rate
+	}
+
+	return rates, nil
+}
+
+func (cs *currencyService) GetExchangeRates() ([]entity.ExchangeRate, error) {
+	return cs.currencyRepository.SelectExchangeRates()
+}
+
+// SetExchangeRates creates or replaces the given rates, other rates are kept.
+func (cs *currencyService) SetExchangeRates(userID uint, rates map[string]string) error {
+	isAdmin, err := cs.adminChecker.IsAdmin(userID)
+	if err != nil {
+		return &apperror.CurrencyError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check user role",
+			Err:     err,
+		}
+	}
+	if !isAdmin {
+		return apperror.ErrCurrencyAccessDenied
+	}
+
+	parsed := make([]Rate, 0, len(rates))
+	for code, value := range rates {
+		rate, err := parseRate(code, value)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, rate)
+	}
+
+	return cs.currencyRepository.UpsertExchangeRates(parsed)
+}
+
+// LoadRatesFile loads exchange rates from a CSV file with "currency,rate"
+// rows, e.g. "USD,92.5". A header row is skipped.
+func (cs *currencyService) LoadRatesFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var rates []Rate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if line == 1 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		rate, err := parseRate(record[0], record[1])
+		if err != nil {
+			return fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return cs.currencyRepository.UpsertExchangeRates(rates)
+}
+
+func parseRate(code, value string) (Rate, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !money.ValidCurrency(code) {
+		return Rate{}, apperror.ErrCurrencyInvalid
+	}
+
+	value = strings.TrimSpace(value)
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || strings.Contains(value, "/") || rate.Sign() <= 0 {
+		return Rate{}, apperror.ErrExchangeRateInvalid
+	}
+
+	return Rate{Currency: code, Rate: rate}, nil
+}
@@ -0,0 +1,11 @@
+package currency
+
+import (
+	"math/big"
+)
+
+// Rate is the price of one unit of Currency in money.DefaultCurrency.
+type Rate struct {
+	Currency string
+	Rate     *big.Rat
+}
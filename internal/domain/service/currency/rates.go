@@ -0,0 +1,43 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+var ErrRateNotFound = errors.New("no exchange rate for currency")
+
+// Rates maps currencies to their price in money.DefaultCurrency.
+type Rates map[string]*big.Rat
+
+// Convert exchanges m into currency through the base currency.
+func (r Rates) Convert(m money.Money, currency string) (money.Money, error) {
+	if m.Currency == currency {
+		return m, nil
+	}
+
+	from, err := r.rate(m.Currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	to, err := r.rate(currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return m.Convert(currency, new(big.Rat).Quo(from, to)), nil
+}
+
+func (r Rates) rate(currency string) (*big.Rat, error) {
+	if currency == money.DefaultCurrency {
+		return big.NewRat(1, 1), nil
+	}
+	rate, ok := r[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrRateNotFound, currency)
+	}
+	return rate, nil
+}
@@ -0,0 +1,44 @@
+package offer
+
+import (
+	"errors"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type BundleDirectory interface {
+	GetBundle(bundleID uint, actorID *uint) (entity.Bundle, error)
+}
+
+// checkBundle checks a bundle offer and returns the bundle price as its list
+// price. A bundle offer goes to the store selling the bundle, and a buyer
+// bundle only takes offers from the buyer who assembled it.
+func (os *offerService) checkBundle(offer *Offer) (money.Money, error) {
+	if offer.VariantID != nil {
+		return money.Money{}, apperror.ErrOfferTarget
+	}
+
+	b, err := os.bundleDirectory.GetBundle(*offer.BundleID, &offer.UserID)
+	if errors.Is(err, apperror.ErrBundleNotFound) {
+		return money.Money{}, apperror.ErrOfferBundleNotFound
+	}
+	if err != nil {
+		return money.Money{}, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch offer bundle",
+			Err:     err,
+		}
+	}
+	if b.UserID != nil && *b.UserID != offer.UserID {
+		return money.Money{}, apperror.ErrOfferBundleNotFound
+	}
+
+	if offer.StoreID != 0 && offer.StoreID != b.StoreID {
+		return money.Money{}, apperror.ErrOfferStoreMismatch
+	}
+	offer.StoreID = b.StoreID
+
+	return b.Price, nil
+}
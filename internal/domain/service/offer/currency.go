@@ -0,0 +1,66 @@
+package offer
+
+import (
+	"errors"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type StoreDirectory interface {
+	GetStoreCurrency(storeID uint) (string, error)
+}
+
+type RateProvider interface {
+	GetRates() (currency.Rates, error)
+}
+
+// settlementPrice returns the offered price in the store currency, every
+// negotiation is settled in it. A price without a currency is taken to be in
+// the store currency already.
+func (os *offerService) settlementPrice(storeID uint, price money.Money) (money.Money, error) {
+	storeCurrency, err := os.storeCurrency(storeID)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	if price.Currency == "" || price.Currency == storeCurrency {
+		return price.WithCurrency(storeCurrency), nil
+	}
+	if !money.ValidCurrency(price.Currency) {
+		return money.Money{}, apperror.ErrOfferCurrency
+	}
+
+	rates, err := os.rateProvider.GetRates()
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	converted, err := rates.Convert(price, storeCurrency)
+	if errors.Is(err, currency.ErrRateNotFound) {
+		return money.Money{}, &apperror.OfferError{
+			Code:    apperror.BadRequest,
+			Message: "no exchange rate for the offered currency",
+			Err:     err,
+		}
+	}
+
+	return converted, err
+}
+
+func (os *offerService) storeCurrency(storeID uint) (string, error) {
+	storeCurrency, err := os.storeDirectory.GetStoreCurrency(storeID)
+	if err != nil {
+		return "", &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store currency",
+			Err:     err,
+		}
+	}
+	if storeCurrency == "" {
+		return "", apperror.ErrOfferStoreNotFound
+	}
+
+	return storeCurrency, nil
+}
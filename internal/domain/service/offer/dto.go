@@ -2,16 +2,69 @@ package offer
 
 import (
 	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+const (
+	// StatusPending waits for the store to answer
+	StatusPending = "pending"
+	// StatusCountered waits for the buyer to answer a store counter-offer
+	StatusCountered = "countered"
+	StatusAccepted  = "accepted"
+	StatusRejected  = "rejected"
+	StatusExpired   = "expired"
+	StatusWithdrawn = "withdrawn"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
 )
 
+// Sides of a negotiation
+const (
+	SideBuyer = "buyer"
+	SideStore = "store"
+)
+
+// Offer is made either on a product (or one of its variants) or on a whole
+// bundle.
 type Offer struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	StoreID   uint      `json:"store_id"`
-	Price     float64   `json:"price"`
-	Status    string    `json:"status"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	UserID    uint        `json:"user_id"`
+	ProductID *uint       `json:"product_id,omitempty"`
+	VariantID *uint       `json:"variant_id,omitempty"`
+	BundleID  *uint       `json:"bundle_id,omitempty"`
+	StoreID   uint        `json:"store_id"`
+	Price     money.Money `json:"price"`
+	Status    string      `json:"status"`
+	MaxRounds int         `json:"max_rounds"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Round is a price proposed in the negotiation of an offer. The buyer opens
+// it with the offer and the sides take turns countering.
+type Round struct {
+	OfferID uint
+	UserID  uint
+	Side    string
+	Price   money.Money
+}
+
+// Orders of the store inbox
+const (
+	SortNewest    = "newest"
+	SortPriceHigh = "price_high"
+	SortExpiring  = "expiring"
+)
+
+// StoreOfferFilter narrows the store inbox, zero values don't filter. Prices
+// are in the store currency, the one offers are settled in.
+type StoreOfferFilter struct {
+	Status        string
+	ProductID     *uint
+	MinPrice      *money.Money
+	MaxPrice      *money.Money
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
 }
@@ -0,0 +1,104 @@
+package offer
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+)
+
+const (
+	testStoreID uint = 10
+	testBuyerID uint = 1
+	testStaffID uint = 2
+	testOtherID uint = 3
+)
+
+// fakeRepository serves one offer and records the changes asked of it. The
+// embedded Repository is nil, calling any other method panics.
+type fakeRepository struct {
+	Repository
+
+	offer entity.Offer
+
+	// status changes
+	from, to       string
+	reservationTTL time.Duration
+
+	// counters
+	round    Round
+	offerTTL time.Duration
+
+	// inbox
+	filter StoreOfferFilter
+	sort   string
+}
+
+func (r *fakeRepository) GetOfferByID(uint) (entity.Offer, error) {
+	return r.offer, nil
+}
+
+func (r *fakeRepository) UpdateOfferStatus(_ uint, from, to string) (entity.Offer, error) {
+	r.from, r.to = from, to
+	r.offer.Status = to
+	return r.offer, nil
+}
+
+func (r *fakeRepository) AcceptOffer(_ uint, from string, reservationTTL time.Duration) (entity.Offer, error) {
+	r.from, r.to, r.reservationTTL = from, StatusAccepted, reservationTTL
+	r.offer.Status = StatusAccepted
+	return r.offer, nil
+}
+
+func (r *fakeRepository) CounterOffer(round Round, from, to string, offerTTL time.Duration) (entity.Offer, error) {
+	r.round, r.from, r.to, r.offerTTL = round, from, to, offerTTL
+	r.offer.Status = to
+	r.offer.Price = round.Price
+	return r.offer, nil
+}
+
+func (r *fakeRepository) SelectStoreOffers(
+	_ uint,
+	filter StoreOfferFilter,
+	sort string,
+	_, _ int,
+) ([]entity.Offer, int64, error) {
+	r.filter, r.sort = filter, sort
+	return nil, 0, nil
+}
+
+func (r *fakeRepository) CountStoreOffersByStatus(uint) (map[string]int64, error) {
+	return map[string]int64{StatusPending: 2}, nil
+}
+
+// fakeMembership puts testStaffID on the staff of testStoreID.
+type fakeMembership struct{}
+
+func (fakeMembership) IsStoreMember(storeID, userID uint, _ ...string) (bool, error) {
+	return storeID == testStoreID && userID == testStaffID, nil
+}
+
+// fakeStoreDirectory sells in currency at every store.
+type fakeStoreDirectory struct {
+	currency string
+}
+
+func (d fakeStoreDirectory) GetStoreCurrency(uint) (string, error) {
+	return d.currency, nil
+}
+
+func newTestService(repository *fakeRepository) *offerService {
+	return NewOfferService(
+		repository, nil, fakeStoreDirectory{currency: "USD"}, nil, fakeMembership{}, nil,
+		24*time.Hour, 30*time.Minute, 5,
+	)
+}
+
+func testOffer(status string) entity.Offer {
+	return entity.Offer{
+		ID:        7,
+		UserID:    testBuyerID,
+		StoreID:   testStoreID,
+		Status:    status,
+		MaxRounds: 5,
+	}
+}
@@ -0,0 +1,58 @@
+package offer
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+// GetStoreOffers lists the offers made to the store in the requested order,
+// only to its staff. The price bounds of the filter are read in the store
+// currency. It also returns how many offers the store has in every status,
+// regardless of the filter, for the inbox badges.
+func (os *offerService) GetStoreOffers(
+	userID, storeID uint,
+	filter StoreOfferFilter,
+	sort string,
+	offset, limit int,
+) ([]entity.Offer, int64, map[string]int64, error) {
+	if filter.Status != "" && !ValidStatus(filter.Status) {
+		return nil, 0, nil, apperror.ErrOfferStatus
+	}
+	switch sort {
+	case SortNewest, SortPriceHigh, SortExpiring:
+	default:
+		return nil, 0, nil, apperror.ErrOfferSort
+	}
+
+	if err := os.checkStoreMember(storeID, userID, apperror.ErrOfferInboxDenied); err != nil {
+		return nil, 0, nil, err
+	}
+
+	storeCurrency, err := os.storeCurrency(storeID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	for _, bound := range []**money.Money{&filter.MinPrice, &filter.MaxPrice} {
+		if *bound != nil {
+			price := (*bound).WithCurrency(storeCurrency)
+			*bound = &price
+		}
+	}
+
+	offers, total, err := os.offerRepository.SelectStoreOffers(storeID, filter, sort, offset, limit)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	counts, err := os.offerRepository.CountStoreOffersByStatus(storeID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	badges := make(map[string]int64, len(Statuses))
+	for _, status := range Statuses {
+		badges[status] = counts[status]
+	}
+
+	return offers, total, badges, nil
+}
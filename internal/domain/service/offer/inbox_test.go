@@ -0,0 +1,86 @@
+package offer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+func TestGetStoreOffersReadsBoundsInStoreCurrency(t *testing.T) {
+	tests := []struct {
+		name          string
+		storeCurrency string
+		bound         money.Money
+		want          money.Money
+	}{
+		{name: "same digits", storeCurrency: "USD", bound: money.New(1050, "RUB"), want: money.New(1050, "USD")},
+		{name: "no currency", storeCurrency: "EUR", bound: money.Money{Amount: 1050}, want: money.New(1050, "EUR")},
+		{name: "zero digit store", storeCurrency: "JPY", bound: money.New(150000, "RUB"), want: money.New(1500, "JPY")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{}
+			os := NewOfferService(
+				repository, nil, fakeStoreDirectory{currency: tt.storeCurrency}, nil, fakeMembership{}, nil,
+				24*time.Hour, 30*time.Minute, 5,
+			)
+
+			minPrice, maxPrice := tt.bound, tt.bound
+			filter := StoreOfferFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}
+			if _, _, _, err := os.GetStoreOffers(testStaffID, testStoreID, filter, SortNewest, 0, 20); err != nil {
+				t.Fatalf("GetStoreOffers unexpected error: %v", err)
+			}
+
+			if got := *repository.filter.MinPrice; got != tt.want {
+				t.Errorf("min price = %+v, want %+v", got, tt.want)
+			}
+			if got := *repository.filter.MaxPrice; got != tt.want {
+				t.Errorf("max price = %+v, want %+v", got, tt.want)
+			}
+			if minPrice != tt.bound {
+				t.Errorf("GetStoreOffers changed the caller's bound to %+v", minPrice)
+			}
+		})
+	}
+}
+
+func TestGetStoreOffers(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  uint
+		status  string
+		sort    string
+		wantErr error
+	}{
+		{name: "staff", userID: testStaffID, sort: SortExpiring},
+		{name: "buyer", userID: testBuyerID, sort: SortExpiring, wantErr: apperror.ErrOfferInboxDenied},
+		{name: "unknown sort", userID: testStaffID, sort: "cheapest", wantErr: apperror.ErrOfferSort},
+		{name: "unknown status", userID: testStaffID, status: "open", sort: SortNewest, wantErr: apperror.ErrOfferStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{}
+			os := newTestService(repository)
+
+			filter := StoreOfferFilter{Status: tt.status}
+			_, _, badges, err := os.GetStoreOffers(tt.userID, testStoreID, filter, tt.sort, 0, 20)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetStoreOffers error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if repository.sort != tt.sort {
+				t.Errorf("SelectStoreOffers sort = %s, want %s", repository.sort, tt.sort)
+			}
+			if len(badges) != len(Statuses) || badges[StatusPending] != 2 || badges[StatusExpired] != 0 {
+				t.Errorf("badges = %v, want every status with 2 pending", badges)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package offer
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type StoreMembership interface {
+	IsStoreMember(storeID, userID uint, roles ...string) (bool, error)
+}
+
+// CounterOffer answers the offer with another price, the next round of its
+// negotiation: the store staff counter a pending offer and the buyer counters
+// back a countered one. The offer then waits for the other side at the new
+// price, which gets a full offer TTL to answer before the offer expires.
+func (os *offerService) CounterOffer(userID, offerID uint, price money.Money) (entity.Offer, error) {
+	current, err := os.offerRepository.GetOfferByID(offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	round := Round{OfferID: offerID, UserID: userID}
+	var next string
+	switch current.Status {
+	case StatusPending:
+		round.Side, next = SideStore, StatusCountered
+		if err := os.checkStoreMember(current.StoreID, userID, apperror.ErrOfferCounterDenied); err != nil {
+			return entity.Offer{}, err
+		}
+	case StatusCountered:
+		round.Side, next = SideBuyer, StatusPending
+		if current.UserID != userID {
+			return entity.Offer{}, apperror.ErrOfferCounterDenied
+		}
+	default:
+		return entity.Offer{}, apperror.ErrOfferTransition
+	}
+
+	if len(current.Rounds) >= current.MaxRounds {
+		return entity.Offer{}, apperror.ErrOfferRoundLimit
+	}
+
+	if round.Price, err = os.settlementPrice(current.StoreID, price); err != nil {
+		return entity.Offer{}, err
+	}
+	if !round.Price.IsPositive() {
+		return entity.Offer{}, apperror.ErrOfferPrice
+	}
+
+	return os.offerRepository.CounterOffer(round, current.Status, next, os.offerTTL)
+}
+
+// checkStatusActor returns ErrOfferStatusDenied unless the user is on the side
+// of the offer that may move it to status. Like counters, an offer is answered
+// by the side it waits for: the store staff accept or reject a pending offer
+// and the buyer a countered one. The staff complete an accepted offer, the
+// buyer withdraws one and either side cancels an accepted one.
+func (os *offerService) checkStatusActor(current entity.Offer, status string, userID uint) error {
+	switch status {
+	case StatusWithdrawn:
+		if current.UserID != userID {
+			return apperror.ErrOfferStatusDenied
+		}
+		return nil
+	case StatusCancelled:
+		if current.UserID == userID {
+			return nil
+		}
+	case StatusAccepted, StatusRejected:
+		if current.Status == StatusCountered {
+			if current.UserID != userID {
+				return apperror.ErrOfferStatusDenied
+			}
+			return nil
+		}
+	}
+
+	return os.checkStoreMember(current.StoreID, userID, apperror.ErrOfferStatusDenied)
+}
+
+// checkStoreMember returns denied unless the user is on the staff of the
+// store.
+func (os *offerService) checkStoreMember(storeID, userID uint, denied error) error {
+	isMember, err := os.storeMembership.IsStoreMember(storeID, userID, store.RoleOwner, store.RoleStaff)
+	if err != nil {
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store membership",
+			Err:     err,
+		}
+	}
+	if !isMember {
+		return denied
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package offer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+func TestCheckStatusActor(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		status  string
+		userID  uint
+		wantErr error
+	}{
+		{name: "staff accept pending", current: StatusPending, status: StatusAccepted, userID: testStaffID},
+		{name: "staff reject pending", current: StatusPending, status: StatusRejected, userID: testStaffID},
+		{
+			name: "buyer can't accept pending", current: StatusPending, status: StatusAccepted,
+			userID: testBuyerID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{
+			name: "buyer can't reject pending", current: StatusPending, status: StatusRejected,
+			userID: testBuyerID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{name: "buyer accept countered", current: StatusCountered, status: StatusAccepted, userID: testBuyerID},
+		{name: "buyer reject countered", current: StatusCountered, status: StatusRejected, userID: testBuyerID},
+		{
+			name: "staff can't accept countered", current: StatusCountered, status: StatusAccepted,
+			userID: testStaffID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{
+			name: "staff can't reject countered", current: StatusCountered, status: StatusRejected,
+			userID: testStaffID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{name: "buyer withdraw", current: StatusPending, status: StatusWithdrawn, userID: testBuyerID},
+		{
+			name: "staff can't withdraw", current: StatusPending, status: StatusWithdrawn,
+			userID: testStaffID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{name: "staff complete", current: StatusAccepted, status: StatusCompleted, userID: testStaffID},
+		{
+			name: "buyer can't complete", current: StatusAccepted, status: StatusCompleted,
+			userID: testBuyerID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{name: "buyer cancel", current: StatusAccepted, status: StatusCancelled, userID: testBuyerID},
+		{name: "staff cancel", current: StatusAccepted, status: StatusCancelled, userID: testStaffID},
+		{
+			name: "stranger can't cancel", current: StatusAccepted, status: StatusCancelled,
+			userID: testOtherID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{
+			name: "stranger can't accept", current: StatusPending, status: StatusAccepted,
+			userID: testOtherID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+	}
+
+	os := newTestService(&fakeRepository{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := os.checkStatusActor(testOffer(tt.current), tt.status, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkStatusActor(%s -> %s) error = %v, want %v", tt.current, tt.status, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateOfferStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		status  string
+		userID  uint
+		wantErr error
+	}{
+		{name: "staff reject pending", current: StatusPending, status: StatusRejected, userID: testStaffID},
+		{name: "buyer accept countered", current: StatusCountered, status: StatusAccepted, userID: testBuyerID},
+		{name: "staff complete accepted", current: StatusAccepted, status: StatusCompleted, userID: testStaffID},
+		{
+			name: "expired offer", current: StatusExpired, status: StatusAccepted,
+			userID: testStaffID, wantErr: apperror.ErrOfferTransition,
+		},
+		{
+			name: "withdrawn offer", current: StatusWithdrawn, status: StatusRejected,
+			userID: testStaffID, wantErr: apperror.ErrOfferTransition,
+		},
+		{
+			name: "completed offer", current: StatusCompleted, status: StatusCancelled,
+			userID: testBuyerID, wantErr: apperror.ErrOfferTransition,
+		},
+		{
+			name: "rejected offer withdrawn", current: StatusRejected, status: StatusWithdrawn,
+			userID: testBuyerID, wantErr: apperror.ErrOfferTransition,
+		},
+		{
+			name: "pending offer completed", current: StatusPending, status: StatusCompleted,
+			userID: testStaffID, wantErr: apperror.ErrOfferTransition,
+		},
+		{
+			name: "buyer answers own offer", current: StatusPending, status: StatusAccepted,
+			userID: testBuyerID, wantErr: apperror.ErrOfferStatusDenied,
+		},
+		{
+			name: "manual expiry", current: StatusPending, status: StatusExpired,
+			userID: testStaffID, wantErr: apperror.ErrOfferExpireManual,
+		},
+		{
+			name: "counter without price", current: StatusPending, status: StatusCountered,
+			userID: testStaffID, wantErr: apperror.ErrOfferCounterRequired,
+		},
+		{
+			name: "unknown status", current: StatusPending, status: "open",
+			userID: testStaffID, wantErr: apperror.ErrOfferStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{offer: testOffer(tt.current)}
+			os := newTestService(repository)
+
+			got, err := os.UpdateOfferStatus(tt.userID, 7, tt.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateOfferStatus(%s -> %s) error = %v, want %v", tt.current, tt.status, err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if repository.to != "" {
+					t.Errorf("UpdateOfferStatus(%s -> %s) changed the offer", tt.current, tt.status)
+				}
+				return
+			}
+			if repository.from != tt.current || got.Status != tt.status {
+				t.Errorf("UpdateOfferStatus moved the offer from %s to %s, want from %s to %s",
+					repository.from, got.Status, tt.current, tt.status)
+			}
+		})
+	}
+}
+
+func TestUpdateOfferStatusAcceptReserves(t *testing.T) {
+	repository := &fakeRepository{offer: testOffer(StatusPending)}
+	os := newTestService(repository)
+
+	if _, err := os.UpdateOfferStatus(testStaffID, 7, StatusAccepted); err != nil {
+		t.Fatalf("UpdateOfferStatus unexpected error: %v", err)
+	}
+	if repository.reservationTTL != 30*time.Minute {
+		t.Errorf("AcceptOffer reservation TTL = %v, want %v", repository.reservationTTL, 30*time.Minute)
+	}
+}
+
+func TestCounterOffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		rounds   int
+		userID   uint
+		wantSide string
+		wantTo   string
+		wantErr  error
+	}{
+		{name: "staff counter pending", current: StatusPending, rounds: 1, userID: testStaffID,
+			wantSide: SideStore, wantTo: StatusCountered},
+		{name: "buyer counter countered", current: StatusCountered, rounds: 2, userID: testBuyerID,
+			wantSide: SideBuyer, wantTo: StatusPending},
+		{name: "buyer can't counter pending", current: StatusPending, rounds: 1, userID: testBuyerID,
+			wantErr: apperror.ErrOfferCounterDenied},
+		{name: "staff can't counter countered", current: StatusCountered, rounds: 2, userID: testStaffID,
+			wantErr: apperror.ErrOfferCounterDenied},
+		{name: "expired offer", current: StatusExpired, rounds: 1, userID: testStaffID,
+			wantErr: apperror.ErrOfferTransition},
+		{name: "accepted offer", current: StatusAccepted, rounds: 1, userID: testStaffID,
+			wantErr: apperror.ErrOfferTransition},
+		{name: "round limit", current: StatusPending, rounds: 5, userID: testStaffID,
+			wantErr: apperror.ErrOfferRoundLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := testOffer(tt.current)
+			current.Rounds = make([]entity.OfferRound, tt.rounds)
+			repository := &fakeRepository{offer: current}
+			os := newTestService(repository)
+
+			_, err := os.CounterOffer(tt.userID, 7, money.New(900, "USD"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CounterOffer error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if repository.round.Side != tt.wantSide || repository.from != tt.current || repository.to != tt.wantTo {
+				t.Errorf("CounterOffer round %s %s -> %s, want %s %s -> %s",
+					repository.round.Side, repository.from, repository.to, tt.wantSide, tt.current, tt.wantTo)
+			}
+			// Every round gets a full offer TTL to be answered
+			if repository.offerTTL != 24*time.Hour {
+				t.Errorf("CounterOffer offer TTL = %v, want %v", repository.offerTTL, 24*time.Hour)
+			}
+		})
+	}
+}
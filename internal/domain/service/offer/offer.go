@@ -1,6 +1,9 @@
 package offer
 
 import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 )
 
@@ -8,20 +11,85 @@ type Repository interface {
 	InsertOffer(offer Offer) (uint, error)
 	GetOfferByID(offerID uint) (entity.Offer, error)
 	SelectUserOffers(userID uint, limit, offset int) ([]entity.Offer, int64, error)
-	UpdateOfferStatus(offerID uint, status string) (entity.Offer, error)
+	SelectStoreOffers(storeID uint, filter StoreOfferFilter, sort string, offset, limit int) ([]entity.Offer, int64, error)
+	CountStoreOffersByStatus(storeID uint) (map[string]int64, error)
+	UpdateOfferStatus(offerID uint, from, to string) (entity.Offer, error)
 	DeleteOffer(offerID uint) (entity.Offer, error)
+	AcceptOffer(offerID uint, from string, reservationTTL time.Duration) (entity.Offer, error)
+	CounterOffer(round Round, from, to string, offerTTL time.Duration) (entity.Offer, error)
+	ReleaseExpiredReservations(now time.Time, limit int) (int, error)
+	ExpireOffers(now time.Time, limit int) (int, error)
 }
 
 type offerService struct {
-	offerRepository Repository
+	offerRepository  Repository
+	productDirectory ProductDirectory
+	storeDirectory   StoreDirectory
+	bundleDirectory  BundleDirectory
+	storeMembership  StoreMembership
+	rateProvider     RateProvider
+	offerTTL         time.Duration
+	reservationTTL   time.Duration
+	maxRounds        int
 }
 
-func NewOfferService(offerRepository Repository) *offerService {
-	return &offerService{offerRepository: offerRepository}
+func NewOfferService(
+	offerRepository Repository,
+	productDirectory ProductDirectory,
+	storeDirectory StoreDirectory,
+	bundleDirectory BundleDirectory,
+	storeMembership StoreMembership,
+	rateProvider RateProvider,
+	offerTTL time.Duration,
+	reservationTTL time.Duration,
+	maxRounds int,
+) *offerService {
+	return &offerService{
+		offerRepository:  offerRepository,
+		productDirectory: productDirectory,
+		storeDirectory:   storeDirectory,
+		bundleDirectory:  bundleDirectory,
+		storeMembership:  storeMembership,
+		rateProvider:     rateProvider,
+		offerTTL:         offerTTL,
+		reservationTTL:   reservationTTL,
+		maxRounds:        maxRounds,
+	}
 }
 
-func (os *offerService) CreateOffer(offer Offer) (uint, error) {
-	return os.offerRepository.InsertOffer(offer)
+// CreateOffer makes an offer on a product or on the whole price of a bundle.
+// The product must be published and in stock at the store of the offer, which
+// is taken from the product or bundle when missing, and the price must be
+// positive and below the list price. The offer price opens a negotiation of
+// at most the configured number of rounds, each answered within the offer TTL.
+// It returns the offer as stored, with its store and expiration filled in.
+func (os *offerService) CreateOffer(offer Offer) (entity.Offer, error) {
+	if !offer.Price.IsPositive() {
+		return entity.Offer{}, apperror.ErrOfferPrice
+	}
+
+	listPrice, err := os.checkTarget(&offer)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	price, err := os.settlementPrice(offer.StoreID, offer.Price)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if err := os.checkPrice(price, listPrice); err != nil {
+		return entity.Offer{}, err
+	}
+	offer.Price = price
+	offer.MaxRounds = os.maxRounds
+	offer.ExpiresAt = time.Now().Add(os.offerTTL)
+
+	offerID, err := os.offerRepository.InsertOffer(offer)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	return os.offerRepository.GetOfferByID(offerID)
 }
 
 func (os *offerService) GetOffer(offerID uint) (entity.Offer, error) {
@@ -32,10 +100,52 @@ func (os *offerService) GetUserOffers(userID uint, limit, offset int) ([]entity.
 	return os.offerRepository.SelectUserOffers(userID, limit, offset)
 }
 
-func (os *offerService) UpdateOfferStatus(offerID uint, status string) (entity.Offer, error) {
-	return os.offerRepository.UpdateOfferStatus(offerID, status)
+// UpdateOfferStatus moves the offer to status if the state machine allows it
+// from the current one, e.g. accepting or declining the latest price of the
+// negotiation. The repository only applies the change while the offer is
+// still in the status checked here, a concurrent change fails with
+// ErrOfferTransition. Counters carry a price and go through CounterOffer, and
+// only the expiration worker expires offers.
+func (os *offerService) UpdateOfferStatus(userID, offerID uint, status string) (entity.Offer, error) {
+	if !ValidStatus(status) {
+		return entity.Offer{}, apperror.ErrOfferStatus
+	}
+	if status == StatusPending || status == StatusCountered {
+		return entity.Offer{}, apperror.ErrOfferCounterRequired
+	}
+	if status == StatusExpired {
+		return entity.Offer{}, apperror.ErrOfferExpireManual
+	}
+
+	current, err := os.offerRepository.GetOfferByID(offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if err := os.checkStatusActor(current, status, userID); err != nil {
+		return entity.Offer{}, err
+	}
+	if !CanTransition(current.Status, status) {
+		return entity.Offer{}, apperror.ErrOfferTransition
+	}
+
+	if status == StatusAccepted {
+		return os.offerRepository.AcceptOffer(offerID, current.Status, os.reservationTTL)
+	}
+	return os.offerRepository.UpdateOfferStatus(offerID, current.Status, status)
 }
 
 func (os *offerService) DeleteOffer(offerID uint) (entity.Offer, error) {
 	return os.offerRepository.DeleteOffer(offerID)
 }
+
+// ReleaseExpiredReservations cancels up to limit accepted offers whose stock
+// reservation expired and returns how many were cancelled.
+func (os *offerService) ReleaseExpiredReservations(now time.Time, limit int) (int, error) {
+	return os.offerRepository.ReleaseExpiredReservations(now, limit)
+}
+
+// ExpireOffers expires up to limit offers left unanswered past their
+// expiration and returns how many were expired. Both sides are notified.
+func (os *offerService) ExpireOffers(now time.Time, limit int) (int, error) {
+	return os.offerRepository.ExpireOffers(now, limit)
+}
@@ -0,0 +1,38 @@
+package offer
+
+// transitions lists the statuses an offer can move to from each status.
+// Rejected, expired, withdrawn, completed and cancelled offers are final.
+var transitions = map[string][]string{
+	StatusPending:   {StatusAccepted, StatusRejected, StatusCountered, StatusExpired, StatusWithdrawn},
+	StatusCountered: {StatusAccepted, StatusRejected, StatusPending, StatusExpired, StatusWithdrawn},
+	StatusAccepted:  {StatusCompleted, StatusCancelled},
+}
+
+// OpenStatuses are the statuses of offers still under negotiation.
+var OpenStatuses = []string{StatusPending, StatusCountered}
+
+// Statuses are all the offer statuses.
+var Statuses = []string{
+	StatusPending, StatusCountered, StatusAccepted, StatusRejected,
+	StatusExpired, StatusWithdrawn, StatusCompleted, StatusCancelled,
+}
+
+// ValidStatus reports whether status is an offer status.
+func ValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether an offer can move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
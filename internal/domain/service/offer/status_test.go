@@ -0,0 +1,38 @@
+package offer
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[string][]string{
+		StatusPending:   {StatusAccepted, StatusRejected, StatusCountered, StatusExpired, StatusWithdrawn},
+		StatusCountered: {StatusAccepted, StatusRejected, StatusPending, StatusExpired, StatusWithdrawn},
+		StatusAccepted:  {StatusCompleted, StatusCancelled},
+	}
+
+	for _, from := range Statuses {
+		for _, to := range Statuses {
+			want := false
+			for _, status := range allowed[from] {
+				if status == to {
+					want = true
+				}
+			}
+			if got := CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestValidStatus(t *testing.T) {
+	for _, status := range Statuses {
+		if !ValidStatus(status) {
+			t.Errorf("ValidStatus(%s) = false", status)
+		}
+	}
+	for _, status := range []string{"", "open", "PENDING"} {
+		if ValidStatus(status) {
+			t.Errorf("ValidStatus(%q) = true", status)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package offer
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type ProductDirectory interface {
+	GetProductByID(id string) (entity.Product, error)
+}
+
+// checkTarget checks that the offer is made on either a product or a bundle
+// and returns the list price of what is offered on.
+func (os *offerService) checkTarget(offer *Offer) (money.Money, error) {
+	if (offer.ProductID == nil) == (offer.BundleID == nil) {
+		return money.Money{}, apperror.ErrOfferTarget
+	}
+	if offer.BundleID != nil {
+		return os.checkBundle(offer)
+	}
+	return os.checkProduct(offer)
+}
+
+// checkProduct checks a product offer against the current product: it must be
+// published, not archived, in stock and sold by the store of the offer. The
+// store is taken from the product when the offer has none. The price of the
+// product, or of the offered variant, is returned as the list price.
+func (os *offerService) checkProduct(offer *Offer) (money.Money, error) {
+	p, err := os.productDirectory.GetProductByID(strconv.FormatUint(uint64(*offer.ProductID), 10))
+	if errors.Is(err, apperror.ErrProductNotFound) {
+		return money.Money{}, apperror.ErrOfferProductNotFound
+	}
+	if err != nil {
+		return money.Money{}, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch offer product",
+			Err:     err,
+		}
+	}
+	if p.Status != product.StatusPublished {
+		return money.Money{}, apperror.ErrOfferProductNotFound
+	}
+	if p.ArchivedAt != nil {
+		return money.Money{}, apperror.ErrOfferProductArchived
+	}
+
+	if offer.StoreID == 0 {
+		offer.StoreID = p.StoreID
+	}
+	if offer.StoreID != p.StoreID {
+		return money.Money{}, apperror.ErrOfferStoreMismatch
+	}
+
+	listPrice, stock := p.Price, p.Quantity
+	if offer.VariantID != nil {
+		variant, ok := findVariant(p.Variants, *offer.VariantID)
+		if !ok {
+			return money.Money{}, apperror.ErrOfferVariantMismatch
+		}
+		listPrice, stock = variant.Price, variant.Stock
+	}
+	if stock <= 0 {
+		return money.Money{}, apperror.ErrOfferOutOfStock
+	}
+
+	return listPrice, nil
+}
+
+// checkPrice checks that the offered price, already in the store currency, is
+// below the list price. A list price in another currency is converted first.
+func (os *offerService) checkPrice(price, listPrice money.Money) error {
+	if listPrice.Currency != price.Currency {
+		rates, err := os.rateProvider.GetRates()
+		if err != nil {
+			return err
+		}
+		if listPrice, err = rates.Convert(listPrice, price.Currency); err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.InternalError,
+				Message: "no exchange rate for the list price currency",
+				Err:     err,
+			}
+		}
+	}
+
+	if price.Cmp(listPrice) >= 0 {
+		return apperror.ErrOfferPriceTooHigh
+	}
+	return nil
+}
+
+func findVariant(variants []entity.ProductVariant, variantID uint) (entity.ProductVariant, bool) {
+	for _, v := range variants {
+		if v.ID == variantID {
+			return v, true
+		}
+	}
+	return entity.ProductVariant{}, false
+}
@@ -0,0 +1,33 @@
+package product
+
+import (
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+)
+
+// checkProductStaff returns ErrProductStaffDenied unless the user is an owner
+// or staff of the store the product belongs to.
+func (ps *productService) checkProductStaff(productID, userID uint) error {
+	product, err := ps.productRepository.GetProductByID(strconv.FormatUint(uint64(productID), 10))
+	if err != nil {
+		return err
+	}
+
+	return ps.checkStoreStaff(product.StoreID, userID)
+}
+
+// checkStoreStaff returns ErrProductStaffDenied unless the user is an owner
+// or staff of the store.
+func (ps *productService) checkStoreStaff(storeID, userID uint) error {
+	isMember, err := ps.storeMembership.IsStoreMember(storeID, userID, store.RoleOwner, store.RoleStaff)
+	if err != nil {
+		return storeMembershipError(err)
+	}
+	if !isMember {
+		return apperror.ErrProductStaffDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package product
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+)
+
+// ArchiveProduct hides the product from listings instead of deleting it, so
+// its offers survive. Pending offers on the product are rejected.
+func (ps *productService) ArchiveProduct(id string, userID uint) error {
+	if err := ps.checkStoreOwner(id, userID); err != nil {
+		return err
+	}
+	return ps.productRepository.ArchiveProduct(id)
+}
+
+func (ps *productService) RestoreProduct(id string, userID uint) error {
+	if err := ps.checkStoreOwner(id, userID); err != nil {
+		return err
+	}
+	return ps.productRepository.RestoreProduct(id)
+}
+
+// checkStoreOwner returns ErrProductAccessDenied unless the user owns the
+// store the product belongs to.
+func (ps *productService) checkStoreOwner(id string, userID uint) error {
+	product, err := ps.productRepository.GetProductByID(id)
+	if err != nil {
+		return err
+	}
+
+	isOwner, err := ps.storeMembership.IsStoreMember(product.StoreID, userID, store.RoleOwner)
+	if err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store membership",
+			Err:     err,
+		}
+	}
+	if !isOwner {
+		return apperror.ErrProductAccessDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+func (ps *productService) CreateAttributeDefinition(userID uint, definition AttributeDefinition) (uint, error) {
+	if err := ps.checkAdmin(userID); err != nil {
+		return 0, err
+	}
+
+	if !attributeKeyPattern.MatchString(definition.Key) {
+		return 0, &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: "attribute key must consist of lowercase letters, digits and underscores",
+		}
+	}
+
+	switch definition.Type {
+	case AttributeEnum:
+		if len(definition.Options) == 0 {
+			return 0, &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: "enum attributes must list their options",
+			}
+		}
+	case AttributeString, AttributeNumber, AttributeBoolean:
+		definition.Options = nil
+	default:
+		return 0, &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: "attribute type must be string, number, enum or boolean",
+		}
+	}
+
+	return ps.productRepository.InsertAttributeDefinition(definition)
+}
+
+func (ps *productService) GetAttributeDefinitions(category string) ([]entity.AttributeDefinition, error) {
+	return ps.productRepository.SelectAttributeDefinitions(category)
+}
+
+func (ps *productService) DeleteAttributeDefinition(userID uint, category string, attributeID uint) error {
+	if err := ps.checkAdmin(userID); err != nil {
+		return err
+	}
+	return ps.productRepository.DeleteAttributeDefinition(category, attributeID)
+}
+
+// SetProductAttributes replaces the attribute values of the product. Every
+// value must match the type of an attribute defined for the product category.
+// Only the store staff can set them.
+func (ps *productService) SetProductAttributes(productID uint, values map[string]interface{}, userID uint) error {
+	product, err := ps.productRepository.GetProductByID(strconv.FormatUint(uint64(productID), 10))
+	if err != nil {
+		return err
+	}
+
+	if err := ps.checkStoreStaff(product.StoreID, userID); err != nil {
+		return err
+	}
+
+	definitions, err := ps.productRepository.SelectAttributeDefinitions(product.Category)
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string]entity.AttributeDefinition, len(definitions))
+	for _, d := range definitions {
+		byKey[d.Key] = d
+	}
+
+	attributeValues := make([]AttributeValue, 0, len(values))
+	for key, raw := range values {
+		definition, ok := byKey[key]
+		if !ok {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: fmt.Sprintf("attribute %q is not defined for category %q", key, product.Category),
+			}
+		}
+
+		value, err := typedAttributeValue(definition, raw)
+		if err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: fmt.Sprintf("invalid value of attribute %q: %v", key, err),
+			}
+		}
+		attributeValues = append(attributeValues, value)
+	}
+
+	return ps.productRepository.ReplaceProductAttributes(productID, attributeValues)
+}
+
+// GetProductFacets counts the products matching the filter per attribute
+// value. An empty storeID counts the products of every store. Unpublished
+// products are only counted for the staff of the store.
+func (ps *productService) GetProductFacets(
+	storeID string,
+	filter ProductFilter,
+	actorID *uint,
+) ([]entity.Facet, error) {
+	filter, err := ps.visibleFilter(storeID, filter, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return ps.productRepository.SelectProductFacets(storeID, filter)
+}
+
+func typedAttributeValue(definition entity.AttributeDefinition, raw interface{}) (AttributeValue, error) {
+	value := AttributeValue{AttributeID: definition.ID}
+
+	switch definition.Type {
+	case AttributeNumber:
+		number, ok := raw.(float64)
+		if !ok {
+			return AttributeValue{}, errors.New("expected a number")
+		}
+		value.Number = &number
+		value.Text = strconv.FormatFloat(number, 'f', -1, 64)
+	case AttributeBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return AttributeValue{}, errors.New("expected a boolean")
+		}
+		value.Bool = &b
+		value.Text = strconv.FormatBool(b)
+	case AttributeEnum:
+		s, ok := raw.(string)
+		if !ok || !slices.Contains(definition.Options, s) {
+			return AttributeValue{}, fmt.Errorf("expected one of %s", strings.Join(definition.Options, ", "))
+		}
+		value.Text = s
+	default:
+		s, ok := raw.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return AttributeValue{}, errors.New("expected a non-empty string")
+		}
+		value.Text = strings.TrimSpace(s)
+	}
+
+	return value, nil
+}
+
+// checkAdmin returns ErrAttributeAccessDenied unless the user is an administrator.
+func (ps *productService) checkAdmin(userID uint) error {
+	isAdmin, err := ps.adminChecker.IsAdmin(userID)
+	if err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check user role",
+			Err:     err,
+		}
+	}
+	if !isAdmin {
+		return apperror.ErrAttributeAccessDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package product
+
+import (
+	"errors"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
+)
+
+type RateProvider interface {
+	GetRates() (currency.Rates, error)
+}
+
+// convertDisplayPrices sets the price of every product in the currency the
+// buyer browses in. Nothing is converted when displayCurrency is empty, and a
+// product priced in a currency without a rate keeps a nil DisplayPrice rather
+// than failing the whole listing.
+func (ps *productService) convertDisplayPrices(products []entity.Product, displayCurrency string) error {
+	if displayCurrency == "" || len(products) == 0 {
+		return nil
+	}
+
+	rates, err := ps.rateProvider.GetRates()
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		price, err := rates.Convert(products[i].Price, displayCurrency)
+		if errors.Is(err, currency.ErrRateNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		products[i].DisplayPrice = &price
+		products[i].DisplayCurrency = displayCurrency
+	}
+
+	return nil
+}
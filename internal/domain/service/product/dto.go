@@ -2,25 +2,150 @@ package product
 
 import (
 	"time"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+// Publication statuses, only published products are listed publicly
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
 )
 
 type Product struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	StoreID     uint      `json:"store_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Category    string    `json:"category"`
-	InStock     bool      `json:"in_stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	StoreID     uint        `json:"store_id"`
+	ExternalSKU *string     `json:"external_sku,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	Category    string      `json:"category"`
+	Quantity    int         `json:"quantity"`
+	Status      string      `json:"status"`
+	PublishAt   *time.Time  `json:"publish_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
 type UpdateProduct struct {
-	StoreID     *uint    `json:"store_id,omitempty"`
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	Category    *string  `json:"category,omitempty"`
-	InStock     *bool    `json:"in_stock,omitempty"`
+	StoreID     *uint        `json:"store_id,omitempty"`
+	ExternalSKU *string      `json:"external_sku,omitempty"`
+	Name        *string      `json:"name,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Price       *money.Money `json:"price,omitempty"`
+	Category    *string      `json:"category,omitempty"`
+	Quantity    *int         `json:"quantity,omitempty"`
+	Status      *string      `json:"status,omitempty"`
+	PublishAt   *time.Time   `json:"publish_at,omitempty"`
+}
+
+// ProductFilter narrows product listings, zero values don't filter.
+type ProductFilter struct {
+	Category   string
+	MinPrice   *money.Money
+	MaxPrice   *money.Money
+	InStock    *bool
+	Status     string
+	Attributes []AttributeFilter
+}
+
+// AttributeFilter matches products whose attribute equals one of Values,
+// compared case-insensitively, and for numbers lies between Min and Max.
+type AttributeFilter struct {
+	Key    string
+	Values []string
+	Min    *float64
+	Max    *float64
+}
+
+type ProductVariant struct {
+	ID         uint              `json:"id"`
+	ProductID  uint              `json:"product_id"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price"`
+	Stock      int               `json:"stock"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type UpdateProductVariant struct {
+	SKU        *string            `json:"sku,omitempty"`
+	Attributes *map[string]string `json:"attributes,omitempty"`
+	Price      *money.Money       `json:"price,omitempty"`
+	Stock      *int               `json:"stock,omitempty"`
+}
+
+type ProductImage struct {
+	ID               uint      `json:"id"`
+	ProductID        uint      `json:"product_id"`
+	ObjectKey        string    `json:"object_key"`
+	ContentType      string    `json:"content_type"`
+	Size             int64     `json:"size"`
+	Position         int       `json:"position"`
+	IsPrimary        bool      `json:"is_primary"`
+	ProcessingStatus string    `json:"processing_status"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+const (
+	ImagePending    = "pending"
+	ImageProcessing = "processing"
+	ImageReady      = "ready"
+	ImageFailed     = "failed"
+)
+
+type ProductImageRendition struct {
+	ImageID     uint   `json:"image_id"`
+	Name        string `json:"name"`
+	ObjectKey   string `json:"object_key"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+}
+
+const (
+	AttributeString  = "string"
+	AttributeNumber  = "number"
+	AttributeEnum    = "enum"
+	AttributeBoolean = "boolean"
+)
+
+type AttributeDefinition struct {
+	ID        uint      `json:"id"`
+	Category  string    `json:"category"`
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options,omitempty"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AttributeValue is a typed value of a product attribute. Text holds the
+// canonical text of every type and is what filters and facets match on.
+type AttributeValue struct {
+	ProductID   uint     `json:"product_id"`
+	AttributeID uint     `json:"attribute_id"`
+	Text        string   `json:"text"`
+	Number      *float64 `json:"number,omitempty"`
+	Bool        *bool    `json:"bool,omitempty"`
+}
+
+// RelatedCandidate is a product scored against another one when related
+// products are refreshed. CoOffers counts the buyers who made offers on both.
+type RelatedCandidate struct {
+	ID          uint
+	Name        string
+	Description string
+	Category    string
+	Price       money.Money
+	CoOffers    int
+}
+
+type RelatedProduct struct {
+	RelatedProductID uint
+	Score            float64
 }
@@ -0,0 +1,212 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/imageproc"
+)
+
+type ObjectStorage interface {
+	UploadFile(ctx context.Context, objectKey, contentType string, file io.Reader, size int64) error
+	DownloadFile(ctx context.Context, objectKey string) ([]byte, error)
+	DeleteFile(ctx context.Context, objectKey string) error
+	ObjectURL(ctx context.Context, objectKey string) (string, error)
+}
+
+// imageExtensions maps the accepted image MIME types to object key extensions
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ImageExtension returns the object key extension of an accepted image MIME
+// type.
+func ImageExtension(contentType string) (string, bool) {
+	ext, ok := imageExtensions[contentType]
+	return ext, ok
+}
+
+// ImageObjectKey returns the storage key of a product image:
+// products/<product id>/images/<name><ext>.
+func ImageObjectKey(productID uint, name, ext string) string {
+	return fmt.Sprintf("products/%d/images/%s%s", productID, name, ext)
+}
+
+// UploadProductImage stores an uploaded image without its metadata, so the
+// location of a phone photo is never published, and queues it for processing.
+func (ps *productService) UploadProductImage(
+	ctx context.Context,
+	productID uint,
+	file io.Reader,
+	size int64,
+	userID uint,
+) (entity.ProductImage, error) {
+	if size > ps.imageMaxSize {
+		return entity.ProductImage{}, apperror.ErrProductImageTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, ps.imageMaxSize+1))
+	if err != nil {
+		return entity.ProductImage{}, &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: "failed to read product image",
+			Err:     err,
+		}
+	}
+	if int64(len(data)) > ps.imageMaxSize {
+		return entity.ProductImage{}, apperror.ErrProductImageTooLarge
+	}
+
+	// The MIME type is sniffed from the content, the client supplied one is not trusted
+	contentType := http.DetectContentType(data)
+	ext, ok := ImageExtension(contentType)
+	if !ok {
+		return entity.ProductImage{}, apperror.ErrProductImageType
+	}
+
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return entity.ProductImage{}, &apperror.ProductError{
+			Code:    apperror.InternalError,
+			Message: "failed to generate image name",
+			Err:     err,
+		}
+	}
+	objectKey := ImageObjectKey(productID, name, ext)
+
+	data, _ = imageproc.StripMetadata(data, contentType)
+	size = int64(len(data))
+	if err := ps.storage.UploadFile(ctx, objectKey, contentType, bytes.NewReader(data), size); err != nil {
+		return entity.ProductImage{}, &apperror.ProductError{
+			Code:    apperror.StorageError,
+			Message: "failed to upload product image",
+			Err:     err,
+		}
+	}
+
+	image, err := ps.productRepository.InsertProductImage(ProductImage{
+		ProductID:        productID,
+		ObjectKey:        objectKey,
+		ContentType:      contentType,
+		Size:             size,
+		ProcessingStatus: ImagePending,
+	})
+	if err != nil {
+		if delErr := ps.storage.DeleteFile(ctx, objectKey); delErr != nil {
+			log.Printf("Failed to delete orphaned image %s: %v", objectKey, delErr)
+		}
+		return entity.ProductImage{}, err
+	}
+
+	if err := ps.fillImageURLs(ctx, []entity.ProductImage{image}); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	return image, nil
+}
+
+func (ps *productService) ReorderProductImages(productID uint, imageIDs []uint, userID uint) error {
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return err
+	}
+
+	return ps.productRepository.ReorderProductImages(productID, imageIDs)
+}
+
+func (ps *productService) SetPrimaryProductImage(productID, imageID, userID uint) error {
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return err
+	}
+
+	return ps.productRepository.SetPrimaryProductImage(productID, imageID)
+}
+
+func (ps *productService) DeleteProductImage(ctx context.Context, productID, imageID, userID uint) error {
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return err
+	}
+
+	return ps.deleteProductImage(ctx, productID, imageID)
+}
+
+// deleteProductImage removes the image row, then its original and renditions
+// from the storage.
+func (ps *productService) deleteProductImage(ctx context.Context, productID, imageID uint) error {
+	image, err := ps.productRepository.DeleteProductImage(productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	// The row is already gone, leftover objects are only logged
+	objectKeys := []string{image.ObjectKey}
+	for _, rendition := range image.Renditions {
+		objectKeys = append(objectKeys, rendition.ObjectKey)
+	}
+	for _, objectKey := range objectKeys {
+		if err := ps.storage.DeleteFile(ctx, objectKey); err != nil {
+			log.Printf("Failed to delete image object %s: %v", objectKey, err)
+		}
+	}
+
+	return nil
+}
+
+// fillImageURLs resolves the object keys of images into URLs clients can fetch.
+func (ps *productService) fillImageURLs(ctx context.Context, images []entity.ProductImage) error {
+	for i := range images {
+		url, err := ps.storage.ObjectURL(ctx, images[i].ObjectKey)
+		if err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.StorageError,
+				Message: "failed to resolve product image url",
+				Err:     err,
+			}
+		}
+		images[i].URL = url
+
+		for j := range images[i].Renditions {
+			rendition := &images[i].Renditions[j]
+			if rendition.URL, err = ps.storage.ObjectURL(ctx, rendition.ObjectKey); err != nil {
+				return &apperror.ProductError{
+					Code:    apperror.StorageError,
+					Message: "failed to resolve product image rendition url",
+					Err:     err,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ps *productService) fillProductsImageURLs(ctx context.Context, products []entity.Product) error {
+	for i := range products {
+		if err := ps.fillImageURLs(ctx, products[i].Images); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,132 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/imageproc"
+)
+
+const (
+	// maxImageAttempts is how many times processing is tried before an image is marked failed
+	maxImageAttempts = 3
+	// imageClaimTimeout is how long an image may stay in processing before it is claimed again
+	imageClaimTimeout = 10 * time.Minute
+	renditionQuality  = 85
+)
+
+// imageRenditions are encoded as JPEG only: golang.org/x/image decodes WebP
+// uploads but has no WebP encoder, and a lossy one would need cgo and libwebp.
+var imageRenditions = []struct {
+	name    string
+	maxSide int
+}{
+	{name: "thumbnail", maxSide: 200},
+	{name: "medium", maxSide: 800},
+	{name: "large", maxSide: 1600},
+}
+
+// RenditionObjectKey returns the storage key of a rendition next to its
+// original image: products/<product id>/images/<name>_<rendition>.jpg.
+func RenditionObjectKey(imageKey, rendition string) string {
+	return fmt.Sprintf("%s_%s.jpg", strings.TrimSuffix(imageKey, path.Ext(imageKey)), rendition)
+}
+
+// ProcessPendingImages claims up to limit uploaded images and generates their
+// renditions. It returns the number of claimed images.
+func (ps *productService) ProcessPendingImages(ctx context.Context, limit int) (int, error) {
+	images, err := ps.productRepository.ClaimPendingProductImages(limit, imageClaimTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, image := range images {
+		if err := ps.processImage(ctx, image); err != nil {
+			log.Printf("Failed to process product image %d: %v", image.ID, err)
+			ps.retryImage(image)
+		}
+	}
+
+	return len(images), nil
+}
+
+func (ps *productService) processImage(ctx context.Context, image entity.ProductImage) error {
+	data, err := ps.storage.DownloadFile(ctx, image.ObjectKey)
+	if err != nil {
+		return err
+	}
+
+	img, err := imageproc.Decode(data)
+	if errors.Is(err, imageproc.ErrNotImage) || errors.Is(err, imageproc.ErrTooLarge) {
+		log.Printf("Rejecting product image %d: %v", image.ID, err)
+		return ps.deleteProductImage(ctx, image.ProductID, image.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Originals are stripped on upload, this only catches the ones stored before
+	if stripped, ok := imageproc.StripMetadata(data, image.ContentType); ok {
+		err := ps.storage.UploadFile(ctx, image.ObjectKey, image.ContentType, bytes.NewReader(stripped), int64(len(stripped)))
+		if err != nil {
+			return err
+		}
+	}
+
+	renditions := make([]ProductImageRendition, 0, len(imageRenditions))
+	for _, spec := range imageRenditions {
+		resized := imageproc.Resize(img, spec.maxSide)
+		encoded, err := imageproc.EncodeJPEG(resized, renditionQuality)
+		if err != nil {
+			return err
+		}
+
+		rendition := ProductImageRendition{
+			ImageID:     image.ID,
+			Name:        spec.name,
+			ObjectKey:   RenditionObjectKey(image.ObjectKey, spec.name),
+			ContentType: "image/jpeg",
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+			Size:        int64(len(encoded)),
+		}
+		err = ps.storage.UploadFile(ctx, rendition.ObjectKey, rendition.ContentType, bytes.NewReader(encoded), rendition.Size)
+		if err != nil {
+			return err
+		}
+		renditions = append(renditions, rendition)
+	}
+
+	err = ps.productRepository.CompleteProductImage(image.ID, renditions)
+	if errors.Is(err, apperror.ErrProductImageNotFound) {
+		// The image was deleted while it was processed
+		for _, rendition := range renditions {
+			if delErr := ps.storage.DeleteFile(ctx, rendition.ObjectKey); delErr != nil {
+				log.Printf("Failed to delete rendition %s: %v", rendition.ObjectKey, delErr)
+			}
+		}
+		return nil
+	}
+
+	return err
+}
+
+// retryImage puts the image back in the queue or gives up after maxImageAttempts.
+func (ps *productService) retryImage(image entity.ProductImage) {
+	status := ImagePending
+	if image.Attempts >= maxImageAttempts {
+		status = ImageFailed
+	}
+
+	if err := ps.productRepository.SetProductImageStatus(image.ID, status); err != nil {
+		log.Printf("Failed to update status of product image %d: %v", image.ID, err)
+	}
+}
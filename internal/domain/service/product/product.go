@@ -1,41 +1,260 @@
 package product
 
 import (
+	"context"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
 )
 
 type Repository interface {
-	InsertProduct(product Product) (uint, error)
+	InsertProduct(product Product, actorID *uint) (uint, error)
 	GetProductByID(id string) (entity.Product, error)
-	SelectProducts(offset, limit int) ([]entity.Product, int, error)
-	SelectStoreProducts(id string, offset, limit int) ([]entity.Product, int, error)
-	UpdateProduct(id string, update UpdateProduct) error
+	GetProductBySlug(slug string) (entity.Product, error)
+	GetStoreIDBySlug(slug string) (uint, error)
+	SelectProducts(filter ProductFilter, offset, limit int) ([]entity.Product, int, error)
+	SelectStoreProducts(id string, filter ProductFilter, offset, limit int) ([]entity.Product, int, error)
+	UpdateProduct(id string, update UpdateProduct, ifMatch []int, actorID *uint) (int, error)
+	SelectPriceHistory(productID string, offset, limit int) ([]entity.PriceChange, int, error)
+	InsertProductVariant(variant ProductVariant) (uint, error)
+	UpdateProductVariant(productID, variantID uint, update UpdateProductVariant) error
+	DeleteProductVariant(productID, variantID uint) error
+	InsertProductImage(image ProductImage) (entity.ProductImage, error)
+	ReorderProductImages(productID uint, imageIDs []uint) error
+	SetPrimaryProductImage(productID, imageID uint) error
+	DeleteProductImage(productID, imageID uint) (entity.ProductImage, error)
+	ClaimPendingProductImages(limit int, staleAfter time.Duration) ([]entity.ProductImage, error)
+	CompleteProductImage(imageID uint, renditions []ProductImageRendition) error
+	SetProductImageStatus(imageID uint, status string) error
+	ArchiveProduct(id string) error
+	RestoreProduct(id string) error
+	InsertAttributeDefinition(definition AttributeDefinition) (uint, error)
+	SelectAttributeDefinitions(category string) ([]entity.AttributeDefinition, error)
+	DeleteAttributeDefinition(category string, attributeID uint) error
+	ReplaceProductAttributes(productID uint, values []AttributeValue) error
+	SelectProductFacets(storeID string, filter ProductFilter) ([]entity.Facet, error)
+	SelectRelatedProducts(id string, limit int) ([]entity.Product, error)
+	PublishScheduledProducts(now time.Time, limit int) (int, error)
+	ClaimRelatedRefreshes(staleBefore time.Time, limit int) ([]RelatedCandidate, error)
+	SelectRelatedCandidates(source RelatedCandidate, terms []string, limit int) ([]RelatedCandidate, error)
+	ReplaceRelatedProducts(productID uint, related []RelatedProduct) error
+}
+
+type StoreMembership interface {
+	IsStoreMember(storeID, userID uint, roles ...string) (bool, error)
+}
+
+type AdminChecker interface {
+	IsAdmin(userID uint) (bool, error)
 }
 
 type productService struct {
 	productRepository Repository
+	storeMembership   StoreMembership
+	adminChecker      AdminChecker
+	storage           ObjectStorage
+	rateProvider      RateProvider
+	imageMaxSize      int64
+}
+
+func NewProductService(
+	productRepo Repository,
+	storeMembership StoreMembership,
+	adminChecker AdminChecker,
+	storage ObjectStorage,
+	rateProvider RateProvider,
+	imageMaxSize int64,
+) *productService {
+	return &productService{
+		productRepository: productRepo,
+		storeMembership:   storeMembership,
+		adminChecker:      adminChecker,
+		storage:           storage,
+		rateProvider:      rateProvider,
+		imageMaxSize:      imageMaxSize,
+	}
+}
+
+// CreateProduct adds a product to a store the user works for.
+func (ps *productService) CreateProduct(product Product, userID uint) (uint, error) {
+	if product.Price.Currency != "" && !money.ValidCurrency(product.Price.Currency) {
+		return 0, apperror.ErrProductCurrency
+	}
+
+	if err := ps.checkStoreStaff(product.StoreID, userID); err != nil {
+		return 0, err
+	}
+
+	var err error
+	if product.Status, product.PublishAt, err = publication(product.Status, product.PublishAt, time.Now()); err != nil {
+		return 0, err
+	}
+
+	return ps.productRepository.InsertProduct(product, &userID)
+}
+
+// GetProductByID returns the product, unpublished ones only to the staff of
+// its store.
+func (ps *productService) GetProductByID(id string, actorID *uint) (entity.Product, error) {
+	product, err := ps.productRepository.GetProductByID(id)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := ps.checkVisible(product, actorID); err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := ps.fillImageURLs(context.Background(), product.Images); err != nil {
+		return entity.Product{}, err
+	}
+
+	return product, nil
+}
+
+// GetProductBySlug finds the product by its current or a former slug, the
+// caller compares the slugs to redirect from a former one.
+func (ps *productService) GetProductBySlug(slug string, actorID *uint) (entity.Product, error) {
+	product, err := ps.productRepository.GetProductBySlug(slug)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := ps.checkVisible(product, actorID); err != nil {
+		return entity.Product{}, err
+	}
+
+	if err := ps.fillImageURLs(context.Background(), product.Images); err != nil {
+		return entity.Product{}, err
+	}
+
+	return product, nil
 }
 
-func NewProductService(productRepo Repository) *productService {
-	return &productService{productRepository: productRepo}
+func (ps *productService) GetStoreIDBySlug(slug string) (uint, error) {
+	return ps.productRepository.GetStoreIDBySlug(slug)
 }
 
-func (ps *productService) CreateProduct(product Product) (uint, error) {
-	return ps.productRepository.InsertProduct(product)
+// GetProducts lists the published products of every store.
+func (ps *productService) GetProducts(
+	filter ProductFilter,
+	displayCurrency string,
+	offset, limit int,
+) ([]entity.Product, int, error) {
+	filter.Status = StatusPublished
+	products, total, err := ps.productRepository.SelectProducts(filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := ps.convertDisplayPrices(products, displayCurrency); err != nil {
+		return nil, 0, err
+	}
+
+	if err := ps.fillProductsImageURLs(context.Background(), products); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
 }
 
-func (ps *productService) GetProductByID(id string) (entity.Product, error) {
-	return ps.productRepository.GetProductByID(id)
+// GetStoreProducts lists the products of a store. Its staff also sees drafts
+// and scheduled products, everyone else only published ones.
+func (ps *productService) GetStoreProducts(
+	id string,
+	filter ProductFilter,
+	displayCurrency string,
+	offset, limit int,
+	actorID *uint,
+) ([]entity.Product, int, error) {
+	filter, err := ps.visibleFilter(id, filter, actorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	products, total, err := ps.productRepository.SelectStoreProducts(id, filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := ps.convertDisplayPrices(products, displayCurrency); err != nil {
+		return nil, 0, err
+	}
+
+	if err := ps.fillProductsImageURLs(context.Background(), products); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
 }
 
-func (ps *productService) GetProducts(offset, limit int) ([]entity.Product, int, error) {
-	return ps.productRepository.SelectProducts(offset, limit)
+// UpdateProduct updates the product and returns its new version. A non-empty
+// ifMatch lists the versions the caller expects the product to be at. Only
+// the store staff can update a product, and only move it to another store
+// they work for.
+func (ps *productService) UpdateProduct(
+	id string,
+	updateProduct UpdateProduct,
+	ifMatch []int,
+	userID uint,
+) (int, error) {
+	if updateProduct.Status != nil {
+		status, publishAt, err := publication(*updateProduct.Status, updateProduct.PublishAt, time.Now())
+		if err != nil {
+			return 0, err
+		}
+		updateProduct.Status, updateProduct.PublishAt = &status, publishAt
+	} else if updateProduct.PublishAt != nil {
+		return 0, apperror.ErrProductPublishAt
+	}
+
+	current, err := ps.productRepository.GetProductByID(id)
+	if err != nil {
+		return 0, err
+	}
+	if err := ps.checkStoreStaff(current.StoreID, userID); err != nil {
+		return 0, err
+	}
+	if updateProduct.StoreID != nil {
+		if err := ps.checkStoreStaff(*updateProduct.StoreID, userID); err != nil {
+			return 0, err
+		}
+	}
+
+	return ps.productRepository.UpdateProduct(id, updateProduct, ifMatch, &userID)
 }
 
-func (ps *productService) GetStoreProducts(id string, offset, limit int) ([]entity.Product, int, error) {
-	return ps.productRepository.SelectStoreProducts(id, offset, limit)
+func (ps *productService) GetPriceHistory(productID string, offset, limit int) ([]entity.PriceChange, int, error) {
+	return ps.productRepository.SelectPriceHistory(productID, offset, limit)
+}
+
+func (ps *productService) CreateProductVariant(productID uint, variant ProductVariant, userID uint) (uint, error) {
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return 0, err
+	}
+
+	variant.ProductID = productID
+	return ps.productRepository.InsertProductVariant(variant)
 }
 
-func (ps *productService) UpdateProduct(id string, updateProduct UpdateProduct) error {
-	return ps.productRepository.UpdateProduct(id, updateProduct)
+func (ps *productService) UpdateProductVariant(
+	productID, variantID uint,
+	update UpdateProductVariant,
+	userID uint,
+) error {
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return err
+	}
+
+	return ps.productRepository.UpdateProductVariant(productID, variantID, update)
+}
+
+func (ps *productService) DeleteProductVariant(productID, variantID, userID uint) error {
+	if err := ps.checkProductStaff(productID, userID); err != nil {
+		return err
+	}
+
+	return ps.productRepository.DeleteProductVariant(productID, variantID)
 }
@@ -0,0 +1,92 @@
+package product
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+)
+
+// PublishScheduledProducts publishes up to limit scheduled products whose
+// publication time has come and returns how many were published.
+func (ps *productService) PublishScheduledProducts(now time.Time, limit int) (int, error) {
+	return ps.productRepository.PublishScheduledProducts(now, limit)
+}
+
+// publication validates a publication status and returns the publication
+// time to store with it: the planned one for scheduled products, now for
+// published ones and none for drafts. An empty status publishes.
+func publication(status string, publishAt *time.Time, now time.Time) (string, *time.Time, error) {
+	switch status {
+	case StatusScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return "", nil, apperror.ErrProductPublishAt
+		}
+		return status, publishAt, nil
+	case StatusDraft, StatusPublished, "":
+		if publishAt != nil {
+			return "", nil, apperror.ErrProductPublishAt
+		}
+		if status == StatusDraft {
+			return status, nil, nil
+		}
+		return StatusPublished, &now, nil
+	default:
+		return "", nil, apperror.ErrProductStatus
+	}
+}
+
+// visibleFilter restricts a listing to published products unless it is
+// scoped to a store the actor works for. Asking for other statuses without
+// being a member is denied.
+func (ps *productService) visibleFilter(storeID string, filter ProductFilter, actorID *uint) (ProductFilter, error) {
+	if filter.Status == StatusPublished {
+		return filter, nil
+	}
+
+	if id, err := strconv.ParseUint(storeID, 10, 32); err == nil && actorID != nil {
+		isMember, err := ps.storeMembership.IsStoreMember(uint(id), *actorID, store.RoleOwner, store.RoleStaff)
+		if err != nil {
+			return ProductFilter{}, storeMembershipError(err)
+		}
+		if isMember {
+			return filter, nil
+		}
+	}
+
+	if filter.Status != "" {
+		return ProductFilter{}, apperror.ErrProductUnpublishedDenied
+	}
+	filter.Status = StatusPublished
+	return filter, nil
+}
+
+// checkVisible hides unpublished products from everyone but the staff of
+// their store.
+func (ps *productService) checkVisible(product entity.Product, actorID *uint) error {
+	if product.Status == StatusPublished {
+		return nil
+	}
+
+	if actorID != nil {
+		isMember, err := ps.storeMembership.IsStoreMember(product.StoreID, *actorID, store.RoleOwner, store.RoleStaff)
+		if err != nil {
+			return storeMembershipError(err)
+		}
+		if isMember {
+			return nil
+		}
+	}
+
+	return apperror.ErrProductNotFound
+}
+
+func storeMembershipError(err error) error {
+	return &apperror.ProductError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to check store membership",
+		Err:     err,
+	}
+}
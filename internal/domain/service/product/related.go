@@ -0,0 +1,189 @@
+package product
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+const (
+	// relatedLimit is how many related products are kept per product
+	relatedLimit = 20
+	// relatedCandidateLimit is how many candidates each signal contributes
+	relatedCandidateLimit = 100
+
+	categoryWeight = 0.3
+	priceWeight    = 0.2
+	textWeight     = 0.25
+	coOfferWeight  = 0.25
+)
+
+// GetRelatedProducts returns the related products computed by the last
+// refresh, best ranked first.
+func (ps *productService) GetRelatedProducts(id, displayCurrency string, limit int) ([]entity.Product, error) {
+	products, err := ps.productRepository.SelectRelatedProducts(id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.convertDisplayPrices(products, displayCurrency); err != nil {
+		return nil, err
+	}
+
+	if err := ps.fillProductsImageURLs(context.Background(), products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// RefreshRelatedProducts recomputes the related products of up to limit
+// products last refreshed before staleBefore. It returns the number of
+// claimed products.
+func (ps *productService) RefreshRelatedProducts(ctx context.Context, staleBefore time.Time, limit int) (int, error) {
+	sources, err := ps.productRepository.ClaimRelatedRefreshes(staleBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(sources) == 0 {
+		return 0, nil
+	}
+
+	rates, err := ps.rateProvider.GetRates()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, source := range sources {
+		if ctx.Err() != nil {
+			break
+		}
+
+		candidates, err := ps.productRepository.SelectRelatedCandidates(
+			source,
+			searchTerms(source.Name),
+			relatedCandidateLimit,
+		)
+		if err != nil {
+			log.Printf("Failed to fetch related candidates of product %d: %v", source.ID, err)
+			continue
+		}
+
+		related := rankRelated(source, candidates, rates)
+		if err := ps.productRepository.ReplaceRelatedProducts(source.ID, related); err != nil {
+			log.Printf("Failed to save related products of product %d: %v", source.ID, err)
+		}
+	}
+
+	return len(sources), nil
+}
+
+// rankRelated scores every candidate as a weighted sum of a shared category,
+// a similar price, similar names and descriptions and shared buyers, and
+// keeps the best relatedLimit.
+func rankRelated(
+	source RelatedCandidate,
+	candidates []RelatedCandidate,
+	rates currency.Rates,
+) []RelatedProduct {
+	maxCoOffers := 0
+	for _, c := range candidates {
+		maxCoOffers = max(maxCoOffers, c.CoOffers)
+	}
+
+	sourceName, sourceDescription := textTokens(source.Name), textTokens(source.Description)
+
+	related := make([]RelatedProduct, 0, len(candidates))
+	for _, c := range candidates {
+		if c.ID == source.ID {
+			continue
+		}
+
+		var score float64
+		if source.Category != "" && strings.EqualFold(c.Category, source.Category) {
+			score += categoryWeight
+		}
+		score += priceWeight * priceSimilarity(source.Price, c.Price, rates)
+		score += textWeight * (0.75*jaccard(sourceName, textTokens(c.Name)) +
+			0.25*jaccard(sourceDescription, textTokens(c.Description)))
+		if maxCoOffers > 0 {
+			score += coOfferWeight * float64(c.CoOffers) / float64(maxCoOffers)
+		}
+
+		if score > 0 {
+			related = append(related, RelatedProduct{RelatedProductID: c.ID, Score: score})
+		}
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].RelatedProductID < related[j].RelatedProductID
+	})
+	if len(related) > relatedLimit {
+		related = related[:relatedLimit]
+	}
+
+	return related
+}
+
+// priceSimilarity is 1 for equal prices and falls to 0 when one price is half
+// the other. Prices in currencies without a rate are not comparable.
+func priceSimilarity(a, b money.Money, rates currency.Rates) float64 {
+	b, err := rates.Convert(b, a.Currency)
+	if err != nil || !a.IsPositive() || !b.IsPositive() {
+		return 0
+	}
+
+	ratio := float64(min(a.Amount, b.Amount)) / float64(max(a.Amount, b.Amount))
+	if ratio < 0.5 {
+		return 0
+	}
+	return (ratio - 0.5) / 0.5
+}
+
+// textTokens splits s into lowercase words of at least two letters or digits.
+func textTokens(s string) map[string]struct{} {
+	tokens := make(map[string]struct{})
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 2 {
+			tokens[word] = struct{}{}
+		}
+	}
+	return tokens
+}
+
+// searchTerms returns the distinct words of s in a stable order.
+func searchTerms(s string) []string {
+	tokens := textTokens(s)
+	terms := make([]string, 0, len(tokens))
+	for token := range tokens {
+		terms = append(terms, token)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range a {
+		if _, ok := b[token]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
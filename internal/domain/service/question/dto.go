@@ -0,0 +1,17 @@
+package question
+
+// MaxTextLength is the longest question or answer in characters.
+const MaxTextLength = 2000
+
+type Question struct {
+	ProductID uint
+	UserID    uint
+	Text      string
+}
+
+// Moderation is an administrator edit of a question, nil fields are kept.
+type Moderation struct {
+	Question *string
+	Answer   *string
+	Hidden   *bool
+}
@@ -0,0 +1,160 @@
+package question
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/store"
+)
+
+type Repository interface {
+	InsertQuestion(question Question) (entity.ProductQuestion, error)
+	GetQuestion(questionID uint) (entity.ProductQuestion, error)
+	AnswerQuestion(questionID, userID uint, answer string) (entity.ProductQuestion, error)
+	UpdateQuestion(questionID uint, moderation Moderation) (entity.ProductQuestion, error)
+	SelectProductQuestions(productID uint, offset, limit int) ([]entity.ProductQuestion, int, error)
+	SelectStoreQuestions(storeID uint, answered *bool, offset, limit int) ([]entity.ProductQuestion, int, error)
+}
+
+type StoreMembership interface {
+	IsStoreMember(storeID, userID uint, roles ...string) (bool, error)
+}
+
+type AdminChecker interface {
+	IsAdmin(userID uint) (bool, error)
+}
+
+type questionService struct {
+	questionRepository Repository
+	storeMembership    StoreMembership
+	adminChecker       AdminChecker
+}
+
+func NewQuestionService(
+	questionRepo Repository,
+	storeMembership StoreMembership,
+	adminChecker AdminChecker,
+) *questionService {
+	return &questionService{
+		questionRepository: questionRepo,
+		storeMembership:    storeMembership,
+		adminChecker:       adminChecker,
+	}
+}
+
+// AskQuestion publishes a question about the product and notifies the store.
+func (qs *questionService) AskQuestion(question Question) (entity.ProductQuestion, error) {
+	text, err := normalizeText(question.Text)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	question.Text = text
+
+	return qs.questionRepository.InsertQuestion(question)
+}
+
+// AnswerQuestion sets or replaces the answer of a question. Only the staff of
+// the store selling the product can answer, the asker is notified on the
+// first answer.
+func (qs *questionService) AnswerQuestion(userID, questionID uint, answer string) (entity.ProductQuestion, error) {
+	text, err := normalizeText(answer)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	q, err := qs.questionRepository.GetQuestion(questionID)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	if q.HiddenAt != nil {
+		return entity.ProductQuestion{}, apperror.ErrQuestionNotFound
+	}
+
+	if err := qs.checkStoreMember(q.StoreID, userID); err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	return qs.questionRepository.AnswerQuestion(questionID, userID, text)
+}
+
+// GetProductQuestions lists the answered, visible questions of a product.
+func (qs *questionService) GetProductQuestions(
+	productID uint,
+	offset, limit int,
+) ([]entity.ProductQuestion, int, error) {
+	return qs.questionRepository.SelectProductQuestions(productID, offset, limit)
+}
+
+// GetStoreQuestions lists the visible questions about the products of a store
+// for its staff, optionally only answered or unanswered ones.
+func (qs *questionService) GetStoreQuestions(
+	userID, storeID uint,
+	answered *bool,
+	offset, limit int,
+) ([]entity.ProductQuestion, int, error) {
+	if err := qs.checkStoreMember(storeID, userID); err != nil {
+		return nil, 0, err
+	}
+	return qs.questionRepository.SelectStoreQuestions(storeID, answered, offset, limit)
+}
+
+// ModerateQuestion lets an administrator edit or hide a question and its
+// answer.
+func (qs *questionService) ModerateQuestion(
+	userID, questionID uint,
+	moderation Moderation,
+) (entity.ProductQuestion, error) {
+	isAdmin, err := qs.adminChecker.IsAdmin(userID)
+	if err != nil {
+		return entity.ProductQuestion{}, &apperror.QuestionError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check administrator",
+			Err:     err,
+		}
+	}
+	if !isAdmin {
+		return entity.ProductQuestion{}, apperror.ErrQuestionModerationDenied
+	}
+
+	if moderation.Question != nil {
+		text, err := normalizeText(*moderation.Question)
+		if err != nil {
+			return entity.ProductQuestion{}, err
+		}
+		moderation.Question = &text
+	}
+	if moderation.Answer != nil {
+		text, err := normalizeText(*moderation.Answer)
+		if err != nil {
+			return entity.ProductQuestion{}, err
+		}
+		moderation.Answer = &text
+	}
+
+	return qs.questionRepository.UpdateQuestion(questionID, moderation)
+}
+
+func (qs *questionService) checkStoreMember(storeID, userID uint) error {
+	isMember, err := qs.storeMembership.IsStoreMember(storeID, userID, store.RoleOwner, store.RoleStaff)
+	if err != nil {
+		return &apperror.QuestionError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store membership",
+			Err:     err,
+		}
+	}
+	if !isMember {
+		return apperror.ErrQuestionAccessDenied
+	}
+	return nil
+}
+
+func normalizeText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > MaxTextLength {
+		return "", apperror.ErrQuestionText
+	}
+	return text, nil
+}
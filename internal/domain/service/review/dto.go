@@ -0,0 +1,30 @@
+package review
+
+const (
+	// MaxTextLength is the longest review text in characters.
+	MaxTextLength = 5000
+	// MaxPhotos is the number of photos a review can have.
+	MaxPhotos = 5
+)
+
+// Review list orders
+const (
+	SortNewest     = "newest"
+	SortOldest     = "oldest"
+	SortRatingHigh = "rating_high"
+	SortRatingLow  = "rating_low"
+)
+
+type Review struct {
+	ProductID uint
+	UserID    uint
+	Rating    int
+	Text      string
+}
+
+type ReviewPhoto struct {
+	ReviewID    uint
+	ObjectKey   string
+	ContentType string
+	Size        int64
+}
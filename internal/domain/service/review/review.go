@@ -0,0 +1,252 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/pkg/imageproc"
+)
+
+type Repository interface {
+	InsertReview(review Review) (entity.ProductReview, error)
+	GetReview(reviewID uint) (entity.ProductReview, error)
+	DeleteReview(reviewID uint) (entity.ProductReview, error)
+	SelectProductReviews(productID uint, sort string, offset, limit int) ([]entity.ProductReview, int, error)
+	InsertReviewPhoto(photo ReviewPhoto) (entity.ReviewPhoto, error)
+	DeleteReviewPhoto(reviewID, photoID uint) (entity.ReviewPhoto, error)
+}
+
+type ObjectStorage interface {
+	UploadFile(ctx context.Context, objectKey, contentType string, file io.Reader, size int64) error
+	DeleteFile(ctx context.Context, objectKey string) error
+	ObjectURL(ctx context.Context, objectKey string) (string, error)
+}
+
+type reviewService struct {
+	reviewRepository Repository
+	storage          ObjectStorage
+	photoMaxSize     int64
+}
+
+func NewReviewService(reviewRepo Repository, storage ObjectStorage, photoMaxSize int64) *reviewService {
+	return &reviewService{
+		reviewRepository: reviewRepo,
+		storage:          storage,
+		photoMaxSize:     photoMaxSize,
+	}
+}
+
+// PhotoObjectKey returns the storage key of a review photo:
+// products/<product id>/reviews/<review id>/<name><ext>.
+func PhotoObjectKey(productID, reviewID uint, name, ext string) string {
+	return fmt.Sprintf("products/%d/reviews/%d/%s%s", productID, reviewID, name, ext)
+}
+
+// CreateReview publishes a review of the product. The repository only accepts
+// it from a buyer holding an accepted offer on the product.
+func (rs *reviewService) CreateReview(review Review) (entity.ProductReview, error) {
+	if review.Rating < 1 || review.Rating > 5 {
+		return entity.ProductReview{}, apperror.ErrReviewRating
+	}
+
+	review.Text = strings.TrimSpace(review.Text)
+	if utf8.RuneCountInString(review.Text) > MaxTextLength {
+		return entity.ProductReview{}, apperror.ErrReviewText
+	}
+
+	return rs.reviewRepository.InsertReview(review)
+}
+
+// GetProductReviews lists the reviews of a product in the requested order.
+func (rs *reviewService) GetProductReviews(
+	ctx context.Context,
+	productID uint,
+	sort string,
+	offset, limit int,
+) ([]entity.ProductReview, int, error) {
+	switch sort {
+	case SortNewest, SortOldest, SortRatingHigh, SortRatingLow:
+	default:
+		return nil, 0, apperror.ErrReviewSort
+	}
+
+	reviews, total, err := rs.reviewRepository.SelectProductReviews(productID, sort, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range reviews {
+		if err := rs.fillPhotoURLs(ctx, reviews[i].Photos); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return reviews, total, nil
+}
+
+// DeleteReview removes a review of the user together with its photos.
+func (rs *reviewService) DeleteReview(ctx context.Context, userID, reviewID uint) error {
+	if _, err := rs.authorReview(userID, reviewID); err != nil {
+		return err
+	}
+
+	deleted, err := rs.reviewRepository.DeleteReview(reviewID)
+	if err != nil {
+		return err
+	}
+
+	for _, photo := range deleted.Photos {
+		rs.deleteObject(ctx, photo.ObjectKey)
+	}
+
+	return nil
+}
+
+// UploadReviewPhoto stores a photo of the review without its metadata, so the
+// location of a phone photo is never published.
+func (rs *reviewService) UploadReviewPhoto(
+	ctx context.Context,
+	userID, reviewID uint,
+	file io.Reader,
+	size int64,
+) (entity.ReviewPhoto, error) {
+	if size > rs.photoMaxSize {
+		return entity.ReviewPhoto{}, apperror.ErrReviewPhotoTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, rs.photoMaxSize+1))
+	if err != nil {
+		return entity.ReviewPhoto{}, &apperror.ReviewError{
+			Code:    apperror.BadRequest,
+			Message: "failed to read review photo",
+			Err:     err,
+		}
+	}
+	if int64(len(data)) > rs.photoMaxSize {
+		return entity.ReviewPhoto{}, apperror.ErrReviewPhotoTooLarge
+	}
+
+	// The MIME type is sniffed from the content, the client supplied one is not trusted
+	contentType := http.DetectContentType(data)
+	ext, ok := product.ImageExtension(contentType)
+	if !ok {
+		return entity.ReviewPhoto{}, apperror.ErrReviewPhotoType
+	}
+
+	r, err := rs.authorReview(userID, reviewID)
+	if err != nil {
+		return entity.ReviewPhoto{}, err
+	}
+	// InsertReviewPhoto enforces the limit with the review locked, this only
+	// saves uploading a photo that would be rejected
+	if len(r.Photos) >= MaxPhotos {
+		return entity.ReviewPhoto{}, apperror.ErrReviewPhotoLimit
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return entity.ReviewPhoto{}, &apperror.ReviewError{
+			Code:    apperror.InternalError,
+			Message: "failed to generate photo name",
+			Err:     err,
+		}
+	}
+	objectKey := PhotoObjectKey(r.ProductID, reviewID, name, ext)
+
+	data, _ = imageproc.StripMetadata(data, contentType)
+	size = int64(len(data))
+	if err := rs.storage.UploadFile(ctx, objectKey, contentType, bytes.NewReader(data), size); err != nil {
+		return entity.ReviewPhoto{}, &apperror.ReviewError{
+			Code:    apperror.StorageError,
+			Message: "failed to upload review photo",
+			Err:     err,
+		}
+	}
+
+	photo, err := rs.reviewRepository.InsertReviewPhoto(ReviewPhoto{
+		ReviewID:    reviewID,
+		ObjectKey:   objectKey,
+		ContentType: contentType,
+		Size:        size,
+	})
+	if err != nil {
+		rs.deleteObject(ctx, objectKey)
+		return entity.ReviewPhoto{}, err
+	}
+
+	photos := []entity.ReviewPhoto{photo}
+	if err := rs.fillPhotoURLs(ctx, photos); err != nil {
+		return entity.ReviewPhoto{}, err
+	}
+
+	return photos[0], nil
+}
+
+func (rs *reviewService) DeleteReviewPhoto(ctx context.Context, userID, reviewID, photoID uint) error {
+	if _, err := rs.authorReview(userID, reviewID); err != nil {
+		return err
+	}
+
+	photo, err := rs.reviewRepository.DeleteReviewPhoto(reviewID, photoID)
+	if err != nil {
+		return err
+	}
+
+	rs.deleteObject(ctx, photo.ObjectKey)
+	return nil
+}
+
+// authorReview fetches a review only its author may change.
+func (rs *reviewService) authorReview(userID, reviewID uint) (entity.ProductReview, error) {
+	r, err := rs.reviewRepository.GetReview(reviewID)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+	if r.UserID != userID {
+		return entity.ProductReview{}, apperror.ErrReviewAccessDenied
+	}
+	return r, nil
+}
+
+// fillPhotoURLs resolves the object keys of photos into URLs clients can fetch.
+func (rs *reviewService) fillPhotoURLs(ctx context.Context, photos []entity.ReviewPhoto) error {
+	for i := range photos {
+		url, err := rs.storage.ObjectURL(ctx, photos[i].ObjectKey)
+		if err != nil {
+			return &apperror.ReviewError{
+				Code:    apperror.StorageError,
+				Message: "failed to resolve review photo url",
+				Err:     err,
+			}
+		}
+		photos[i].URL = url
+	}
+
+	return nil
+}
+
+// deleteObject removes a photo object without a row, failures are only logged.
+func (rs *reviewService) deleteObject(ctx context.Context, objectKey string) {
+	if err := rs.storage.DeleteFile(ctx, objectKey); err != nil {
+		log.Printf("Failed to delete review photo %s: %v", objectKey, err)
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -4,6 +4,11 @@ import (
 	"time"
 )
 
+const (
+	RoleOwner = "owner"
+	RoleStaff = "staff"
+)
+
 // Store здесь еще надо думать тому, кто возьмется Store реализовывать
 type Store struct {
 	ID          uint   `json:"id" gorm:"primaryKey"`
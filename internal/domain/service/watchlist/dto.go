@@ -0,0 +1,14 @@
+package watchlist
+
+const (
+	ItemProduct = "product"
+	ItemStore   = "store"
+)
+
+// Item is a product or a store on the watchlist of a user, exactly one of
+// ProductID and StoreID is set.
+type Item struct {
+	UserID    uint
+	ProductID *uint
+	StoreID   *uint
+}
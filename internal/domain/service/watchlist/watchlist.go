@@ -0,0 +1,53 @@
+package watchlist
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+)
+
+type Repository interface {
+	InsertWatchlistItem(item Item) error
+	DeleteWatchlistItem(item Item) error
+	SelectWatchlist(userID uint, itemType string, offset, limit int) ([]entity.WatchlistItem, int, error)
+}
+
+type watchlistService struct {
+	watchlistRepository Repository
+}
+
+func NewWatchlistService(watchlistRepo Repository) *watchlistService {
+	return &watchlistService{watchlistRepository: watchlistRepo}
+}
+
+// WatchProduct adds the product to the watchlist of the user, watching an
+// already watched product is a no-op.
+func (ws *watchlistService) WatchProduct(userID, productID uint) error {
+	return ws.watchlistRepository.InsertWatchlistItem(Item{UserID: userID, ProductID: &productID})
+}
+
+func (ws *watchlistService) UnwatchProduct(userID, productID uint) error {
+	return ws.watchlistRepository.DeleteWatchlistItem(Item{UserID: userID, ProductID: &productID})
+}
+
+// WatchStore adds the store to the watchlist of the user, watching an already
+// watched store is a no-op.
+func (ws *watchlistService) WatchStore(userID, storeID uint) error {
+	return ws.watchlistRepository.InsertWatchlistItem(Item{UserID: userID, StoreID: &storeID})
+}
+
+func (ws *watchlistService) UnwatchStore(userID, storeID uint) error {
+	return ws.watchlistRepository.DeleteWatchlistItem(Item{UserID: userID, StoreID: &storeID})
+}
+
+// GetWatchlist lists the watchlist of the user, newest first. An empty
+// itemType lists products and stores together.
+func (ws *watchlistService) GetWatchlist(
+	userID uint,
+	itemType string,
+	offset, limit int,
+) ([]entity.WatchlistItem, int, error) {
+	if itemType != "" && itemType != ItemProduct && itemType != ItemStore {
+		return nil, 0, apperror.ErrWatchlistItemType
+	}
+	return ws.watchlistRepository.SelectWatchlist(userID, itemType, offset, limit)
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/alert"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type AlertService interface {
+	SetPriceAlert(alert alert.PriceAlert) (entity.PriceAlert, error)
+	RearmPriceAlert(userID, alertID uint) (entity.PriceAlert, error)
+	DeletePriceAlert(userID, alertID uint) error
+	GetPriceAlerts(userID uint, offset, limit int) ([]entity.PriceAlert, int, error)
+}
+
+type alertHandler struct {
+	alertService AlertService
+}
+
+func NewAlertHandler(alertService AlertService) alertHandler {
+	return alertHandler{alertService: alertService}
+}
+
+func (h *alertHandler) PutProductPriceAlert(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	var req dto.PutPriceAlertReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid price alert",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	priceAlert, err := h.alertService.SetPriceAlert(req.ConvertToSvc(userID, uint(productID)))
+	if err != nil {
+		handleAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, priceAlert)
+}
+
+func (h *alertHandler) GetPriceAlerts(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return
+	}
+
+	offset := (page - 1) * limit
+
+	alerts, total, err := h.alertService.GetPriceAlerts(userID, offset, limit)
+	if err != nil {
+		handleAlertError(c, err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": alerts,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+func (h *alertHandler) PostPriceAlertRearm(c *gin.Context) {
+	userID, alertID, ok := alertParams(c)
+	if !ok {
+		return
+	}
+
+	priceAlert, err := h.alertService.RearmPriceAlert(userID, alertID)
+	if err != nil {
+		handleAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, priceAlert)
+}
+
+func (h *alertHandler) DeletePriceAlert(c *gin.Context) {
+	userID, alertID, ok := alertParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.alertService.DeletePriceAlert(userID, alertID); err != nil {
+		handleAlertError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func alertParams(c *gin.Context) (uint, uint, bool) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	alertID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit alert id",
+		})
+		return 0, 0, false
+	}
+
+	return userID, uint(alertID), true
+}
@@ -15,9 +15,17 @@ import (
 func SetupRouter(
 	productService ProductService,
 	offerService OfferService,
+	catalogService CatalogService,
+	currencyService CurrencyService,
+	watchlistService WatchlistService,
+	alertService AlertService,
+	questionService QuestionService,
+	reviewService ReviewService,
+	bundleService BundleService,
 	s3 *objectstorage.BucketBasics,
 ) *gin.Engine {
 	router := gin.New()
+	registerValidators()
 
 	// Add default middleware
 	router.Use(gin.Logger())
@@ -32,8 +40,18 @@ func SetupRouter(
 		})
 	})
 
+	productHandler := NewProductHandler(productService)
+	offerHandler := NewOfferHandler(offerService)
+	catalogHandler := NewCatalogHandler(catalogService)
+	currencyHandler := NewCurrencyHandler(currencyService)
+	watchlistHandler := NewWatchlistHandler(watchlistService)
+	alertHandler := NewAlertHandler(alertService)
+	questionHandler := NewQuestionHandler(questionService)
+	reviewHandler := NewReviewHandler(reviewService)
+	bundleHandler := NewBundleHandler(bundleService)
+
 	// API routes group
-	// api := router.Group("/api")
+	api := router.Group("/api")
 	{
 		// Public routes
 		// public := api.Group("")
@@ -48,7 +66,7 @@ func SetupRouter(
 		}
 
 		// Protected routes
-		// protected := api.Group("")
+		protected := api.Group("")
 		// protected.Use(middleware.AuthMiddleware())
 		{
 			// User profile
@@ -56,30 +74,118 @@ func SetupRouter(
 			// protected.PUT("/profile", handlers.UpdateProfile(db))
 
 			// Store management
-			// stores := protected.Group("/stores")
-			// {
-			// 	stores.GET("/:id", handlers.GetStore(db))
-			// 	stores.GET("/:id/products", handlers.GetStoreProducts(db))
-			// }
+			stores := protected.Group("/stores")
+			{
+				// stores.GET("/:id", handlers.GetStore(db))
+				stores.GET("/:id/products", productHandler.GetStoreProducts)
+				stores.GET("/by-slug/:slug/products", productHandler.GetStoreProductsBySlug)
+				stores.GET("/:id/products/export", catalogHandler.GetCatalogExport)
+				stores.POST("/:id/products/import", catalogHandler.PostCatalogImport)
+				stores.GET("/:id/products/import/:importID", catalogHandler.GetCatalogImport)
+				stores.GET("/:id/products/import/:importID/errors", catalogHandler.GetCatalogImportErrors)
+				stores.GET("/:id/questions", questionHandler.GetStoreQuestions)
+				stores.GET("/:id/bundles", bundleHandler.GetStoreBundles)
+				stores.POST("/:id/bundles", bundleHandler.PostStoreBundle)
+				stores.GET("/:id/offers", offerHandler.GetStoreOffers)
+			}
 
 			// Product management
-			// products := protected.Group("/products")
-			// {
-			// 	products.GET("", handlers.GetProducts(db))
-			// 	products.GET("/:id", handlers.GetProduct(db))
-			// 	products.PUT("/:id", handlers.UpdateProduct(db))
-			// 	products.POST("", handlers.AddProduct(db))
-			// }
+			products := protected.Group("/products")
+			{
+				products.GET("", productHandler.GetProducts)
+				products.GET("/:id", productHandler.GetProduct)
+				products.GET("/by-slug/:slug", productHandler.GetProductBySlug)
+				products.PATCH("/:id", productHandler.PatchProduct)
+				products.POST("", productHandler.PostProduct)
+				products.DELETE("/:id", productHandler.DeleteProduct)
+				products.POST("/:id/restore", productHandler.RestoreProduct)
+				products.GET("/:id/price-history", productHandler.GetPriceHistory)
+				products.GET("/:id/related", productHandler.GetRelatedProducts)
+				products.GET("/:id/questions", questionHandler.GetProductQuestions)
+				products.POST("/:id/questions", questionHandler.PostProductQuestion)
+				products.GET("/:id/reviews", reviewHandler.GetProductReviews)
+				products.POST("/:id/reviews", reviewHandler.PostProductReview)
+				products.PUT("/:id/price-alert", alertHandler.PutProductPriceAlert)
+
+				products.POST("/:id/variants", productHandler.PostProductVariant)
+				products.PATCH("/:id/variants/:variantID", productHandler.PatchProductVariant)
+				products.DELETE("/:id/variants/:variantID", productHandler.DeleteProductVariant)
+
+				products.PUT("/:id/attributes", productHandler.PutProductAttributes)
+
+				products.POST("/:id/images", productHandler.PostProductImage)
+				products.PATCH("/:id/images", productHandler.PatchProductImages)
+				products.PUT("/:id/images/:imageID/primary", productHandler.PutPrimaryProductImage)
+				products.DELETE("/:id/images/:imageID", productHandler.DeleteProductImage)
+			}
 
 			// Offer management
-			// offers := protected.Group("/offers")
-			// {
-			// 	offers.POST("", handlers.CreateOffer(db))
-			// 	offers.GET("", handlers.GetUserOffers(db))
-			// 	offers.GET("/:id", handlers.GetOffer(db))
-			// 	offers.PUT("/:id/status", handlers.UpdateOfferStatus(db))
-			// 	offers.DELETE("/:id", handlers.CancelOffer(db))
-			// }
+			offers := protected.Group("/offers")
+			{
+				offers.POST("", offerHandler.PostOffer)
+				offers.GET("", offerHandler.GetUserOffers)
+				offers.GET("/:id", offerHandler.GetOffer)
+				offers.PATCH("/:id/status", offerHandler.PatchOfferStatus)
+				offers.POST("/:id/counter", offerHandler.PostCounterOffer)
+				offers.DELETE("/:id", offerHandler.DeleteOffer)
+			}
+
+			// Product bundles, offers on them go through /offers
+			bundles := protected.Group("/bundles")
+			{
+				bundles.POST("", bundleHandler.PostBundle)
+				bundles.GET("/:id", bundleHandler.GetBundle)
+			}
+
+			// Buyer watchlist
+			watchlist := protected.Group("/watchlist")
+			{
+				watchlist.GET("", watchlistHandler.GetWatchlist)
+				watchlist.POST("/products/:id", watchlistHandler.PostWatchedProduct)
+				watchlist.DELETE("/products/:id", watchlistHandler.DeleteWatchedProduct)
+				watchlist.POST("/stores/:id", watchlistHandler.PostWatchedStore)
+				watchlist.DELETE("/stores/:id", watchlistHandler.DeleteWatchedStore)
+			}
+
+			// Product questions
+			questions := protected.Group("/questions")
+			{
+				questions.PUT("/:id/answer", questionHandler.PutQuestionAnswer)
+			}
+
+			// Product reviews
+			reviews := protected.Group("/reviews")
+			{
+				reviews.DELETE("/:id", reviewHandler.DeleteReview)
+				reviews.POST("/:id/photos", reviewHandler.PostReviewPhoto)
+				reviews.DELETE("/:id/photos/:photoID", reviewHandler.DeleteReviewPhoto)
+			}
+
+			// Target-price alerts
+			alerts := protected.Group("/alerts")
+			{
+				alerts.GET("", alertHandler.GetPriceAlerts)
+				alerts.POST("/:id/rearm", alertHandler.PostPriceAlertRearm)
+				alerts.DELETE("/:id", alertHandler.DeletePriceAlert)
+			}
+
+			// Category attributes
+			categories := protected.Group("/categories")
+			{
+				categories.GET("/:category/attributes", productHandler.GetCategoryAttributes)
+			}
+
+			// Exchange rates
+			protected.GET("/exchange-rates", currencyHandler.GetExchangeRates)
+
+			// Administration
+			admin := protected.Group("/admin")
+			{
+				admin.PUT("/exchange-rates", currencyHandler.PutExchangeRates)
+				admin.POST("/categories/:category/attributes", productHandler.PostCategoryAttribute)
+				admin.DELETE("/categories/:category/attributes/:attributeID", productHandler.DeleteCategoryAttribute)
+				admin.PATCH("/questions/:id", questionHandler.PatchQuestion)
+			}
 
 			// Notification management
 			// notifications := protected.Group("/notifications")
@@ -102,8 +208,18 @@ func handleProductError(c *gin.Context, err error) {
 		switch productErr.Code {
 		case apperror.NotFound:
 			status = http.StatusNotFound
-		case apperror.DuplicateError:
+		case apperror.DuplicateError, apperror.VariantInUse:
 			status = http.StatusConflict
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.TooLarge:
+			status = http.StatusRequestEntityTooLarge
+		case apperror.UnsupportedMedia:
+			status = http.StatusUnsupportedMediaType
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.VersionMismatch:
+			status = http.StatusPreconditionFailed
 		case apperror.DatabaseError:
 			status = http.StatusInternalServerError
 		}
@@ -127,10 +243,16 @@ func handleOfferError(c *gin.Context, err error) {
 		status := http.StatusInternalServerError
 
 		switch offerError.Code {
-		case apperror.NotFound:
+		case apperror.NotFound, apperror.ProductNotFound:
 			status = http.StatusNotFound
 		case apperror.DuplicateError:
 			status = http.StatusConflict
+		case apperror.BadRequest, apperror.StoreMismatch, apperror.InvalidPrice, apperror.PriceTooHigh:
+			status = http.StatusBadRequest
+		case apperror.OutOfStock, apperror.ProductArchived, apperror.InvalidStatus, apperror.RoundLimit:
+			status = http.StatusConflict
+		case apperror.Forbidden:
+			status = http.StatusForbidden
 		case apperror.DatabaseError:
 			status = http.StatusInternalServerError
 		}
@@ -147,3 +269,212 @@ func handleOfferError(c *gin.Context, err error) {
 		"message": "An unexpected error occurred",
 	})
 }
+
+func handleCatalogError(c *gin.Context, err error) {
+	var catalogErr *apperror.CatalogError
+	if errors.As(err, &catalogErr) {
+		status := http.StatusInternalServerError
+
+		switch catalogErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.TooLarge:
+			status = http.StatusRequestEntityTooLarge
+		case apperror.UnsupportedMedia:
+			status = http.StatusUnsupportedMediaType
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DatabaseError, apperror.StorageError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    catalogErr.Code,
+			"message": catalogErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleCurrencyError(c *gin.Context, err error) {
+	var currencyErr *apperror.CurrencyError
+	if errors.As(err, &currencyErr) {
+		status := http.StatusInternalServerError
+
+		switch currencyErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    currencyErr.Code,
+			"message": currencyErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleWatchlistError(c *gin.Context, err error) {
+	var watchlistErr *apperror.WatchlistError
+	if errors.As(err, &watchlistErr) {
+		status := http.StatusInternalServerError
+
+		switch watchlistErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    watchlistErr.Code,
+			"message": watchlistErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleAlertError(c *gin.Context, err error) {
+	var alertErr *apperror.AlertError
+	if errors.As(err, &alertErr) {
+		status := http.StatusInternalServerError
+
+		switch alertErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    alertErr.Code,
+			"message": alertErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleQuestionError(c *gin.Context, err error) {
+	var questionErr *apperror.QuestionError
+	if errors.As(err, &questionErr) {
+		status := http.StatusInternalServerError
+
+		switch questionErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    questionErr.Code,
+			"message": questionErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleReviewError(c *gin.Context, err error) {
+	var reviewErr *apperror.ReviewError
+	if errors.As(err, &reviewErr) {
+		status := http.StatusInternalServerError
+
+		switch reviewErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.DuplicateError:
+			status = http.StatusConflict
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.TooLarge:
+			status = http.StatusRequestEntityTooLarge
+		case apperror.UnsupportedMedia:
+			status = http.StatusUnsupportedMediaType
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DatabaseError, apperror.StorageError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    reviewErr.Code,
+			"message": reviewErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleBundleError(c *gin.Context, err error) {
+	var bundleErr *apperror.BundleError
+	if errors.As(err, &bundleErr) {
+		status := http.StatusInternalServerError
+
+		switch bundleErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    bundleErr.Code,
+			"message": bundleErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
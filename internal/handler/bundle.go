@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/bundle"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type BundleService interface {
+	CreateStoreBundle(userID uint, bundle bundle.Bundle) (entity.Bundle, error)
+	CreateBuyerBundle(userID uint, bundle bundle.Bundle) (entity.Bundle, error)
+	GetBundle(bundleID uint, actorID *uint) (entity.Bundle, error)
+	GetStoreBundles(storeID uint, offset, limit int) ([]entity.Bundle, int, error)
+}
+
+type bundleHandler struct {
+	bundleService BundleService
+}
+
+func NewBundleHandler(bundleService BundleService) bundleHandler {
+	return bundleHandler{bundleService: bundleService}
+}
+
+// PostBundle assembles a buyer bundle from products of a single store.
+func (h *bundleHandler) PostBundle(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindBundle(c)
+	if !ok {
+		return
+	}
+
+	b, err := h.bundleService.CreateBuyerBundle(userID, req.ConvertToSvc(0))
+	if err != nil {
+		handleBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, b)
+}
+
+func (h *bundleHandler) PostStoreBundle(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	storeID, ok := bundleIDParam(c, "store")
+	if !ok {
+		return
+	}
+
+	req, ok := bindBundle(c)
+	if !ok {
+		return
+	}
+
+	b, err := h.bundleService.CreateStoreBundle(userID, req.ConvertToSvc(storeID))
+	if err != nil {
+		handleBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, b)
+}
+
+func (h *bundleHandler) GetBundle(c *gin.Context) {
+	bundleID, ok := bundleIDParam(c, "bundle")
+	if !ok {
+		return
+	}
+
+	b, err := h.bundleService.GetBundle(bundleID, actorID(c))
+	if err != nil {
+		handleBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, b)
+}
+
+func (h *bundleHandler) GetStoreBundles(c *gin.Context) {
+	storeID, ok := bundleIDParam(c, "store")
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return
+	}
+
+	bundles, total, err := h.bundleService.GetStoreBundles(storeID, (page-1)*limit, limit)
+	if err != nil {
+		handleBundleError(c, err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": bundles,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+func bindBundle(c *gin.Context) (dto.PostBundleReq, bool) {
+	var req dto.PostBundleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid bundle data",
+			"details": err.Error(),
+		})
+		return dto.PostBundleReq{}, false
+	}
+	return req, true
+}
+
+func bundleIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit " + name + " id",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/catalog"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogService interface {
+	StartImport(
+		ctx context.Context,
+		storeID, userID uint,
+		format string,
+		file io.Reader,
+		size int64,
+	) (entity.CatalogImport, error)
+	GetImport(storeID, importID, userID uint) (entity.CatalogImport, error)
+	GetImportErrors(storeID, importID, userID uint) ([]entity.CatalogImportError, error)
+	ExportCatalog(
+		ctx context.Context,
+		storeID, userID uint,
+		format string,
+		filter product.ProductFilter,
+		w io.Writer,
+	) error
+}
+
+type catalogHandler struct {
+	catalogService CatalogService
+}
+
+func NewCatalogHandler(catalogService CatalogService) *catalogHandler {
+	return &catalogHandler{catalogService: catalogService}
+}
+
+// catalogFormats maps file extensions to catalog formats when ?format= is omitted
+var catalogFormats = map[string]string{
+	".csv":    catalog.FormatCSV,
+	".ndjson": catalog.FormatNDJSON,
+	".jsonl":  catalog.FormatNDJSON,
+}
+
+func (h *catalogHandler) PostCatalogImport(c *gin.Context) {
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit store id",
+		})
+		return
+	}
+
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Catalog file is required",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = catalogFormats[strings.ToLower(filepath.Ext(fileHeader.Filename))]
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid catalog file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	catalogImport, err := h.catalogService.StartImport(
+		c.Request.Context(), uint(storeID), userID, format, file, fileHeader.Size,
+	)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, catalogImport)
+}
+
+func (h *catalogHandler) GetCatalogImport(c *gin.Context) {
+	storeID, importID, ok := catalogImportParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	catalogImport, err := h.catalogService.GetImport(storeID, importID, userID)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, catalogImport)
+}
+
+// GetCatalogImportErrors downloads the rejected rows of an import as CSV.
+func (h *catalogHandler) GetCatalogImportErrors(c *gin.Context) {
+	storeID, importID, ok := catalogImportParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	rowErrors, err := h.catalogService.GetImportErrors(storeID, importID, userID)
+	if err != nil {
+		handleCatalogError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-errors.csv"`, importID))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"line", "external_sku", "message"})
+	for _, e := range rowErrors {
+		_ = writer.Write([]string{strconv.Itoa(e.Line), e.ExternalSKU, e.Message})
+	}
+	writer.Flush()
+}
+
+// GetCatalogExport streams the store catalog as CSV or NDJSON. It accepts the
+// same filters as the store product listing.
+func (h *catalogHandler) GetCatalogExport(c *gin.Context) {
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit store id",
+		})
+		return
+	}
+
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	filter, ok := parseProductFilter(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", catalog.FormatCSV))
+	c.Header("Content-Type", catalog.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="store-%d-products.%s"`, storeID, format))
+
+	err = h.catalogService.ExportCatalog(c.Request.Context(), uint(storeID), userID, format, filter, c.Writer)
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		// The response is already streaming, the client sees a truncated file
+		log.Printf("Failed to export catalog of store %d: %v", storeID, err)
+		return
+	}
+
+	// gin keeps a Content-Type that is already set, the error is JSON
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	handleCatalogError(c, err)
+}
+
+func catalogImportParams(c *gin.Context) (uint, uint, bool) {
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit store id",
+		})
+		return 0, 0, false
+	}
+
+	importID, err := strconv.Atoi(c.Param("importID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit import id",
+		})
+		return 0, 0, false
+	}
+
+	return uint(storeID), uint(importID), true
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID returns the id of the authenticated user set by the auth middleware.
+func currentUserID(c *gin.Context) (uint, bool) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		return 0, false
+	}
+
+	id, ok := userID.(uint)
+	return id, ok
+}
+
+// actorID returns the authenticated user id for audit records or nil if the
+// request is anonymous.
+func actorID(c *gin.Context) *uint {
+	if id, ok := currentUserID(c); ok {
+		return &id
+	}
+	return nil
+}
+
+// requireUserID returns the authenticated user id or responds with 401.
+func requireUserID(c *gin.Context) (uint, bool) {
+	id, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "Authentication required",
+		})
+	}
+	return id, ok
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyService interface {
+	GetExchangeRates() ([]entity.ExchangeRate, error)
+	SetExchangeRates(userID uint, rates map[string]string) error
+}
+
+type currencyHandler struct {
+	currencyService CurrencyService
+}
+
+func NewCurrencyHandler(currencyService CurrencyService) *currencyHandler {
+	return &currencyHandler{currencyService: currencyService}
+}
+
+func (h *currencyHandler) GetExchangeRates(c *gin.Context) {
+	rates, err := h.currencyService.GetExchangeRates()
+	if err != nil {
+		handleCurrencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"base":  money.DefaultCurrency,
+		"rates": rates,
+	})
+}
+
+func (h *currencyHandler) PutExchangeRates(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutExchangeRatesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid exchange rates",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.currencyService.SetExchangeRates(userID, req.ConvertToSvc()); err != nil {
+		handleCurrencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rates updated successfully"})
+}
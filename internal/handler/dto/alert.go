@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/alert"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type PutPriceAlertReq struct {
+	TargetPrice money.Money `json:"target_price" binding:"gt=0"`
+}
+
+func (pr *PutPriceAlertReq) ConvertToSvc(userID, productID uint) alert.PriceAlert {
+	return alert.PriceAlert{
+		UserID:      userID,
+		ProductID:   productID,
+		TargetPrice: pr.TargetPrice,
+	}
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/PosokhovVadim/stawberry/internal/domain/service/bundle"
+
+type PostBundleReq struct {
+	Name  string              `json:"name" binding:"required"`
+	Items []PostBundleItemReq `json:"items" binding:"required,dive"`
+}
+
+type PostBundleItemReq struct {
+	ProductID uint  `json:"product_id" binding:"required"`
+	VariantID *uint `json:"variant_id,omitempty"`
+	Quantity  int   `json:"quantity,omitempty" binding:"omitempty,min=1"`
+}
+
+func (pb *PostBundleReq) ConvertToSvc(storeID uint) bundle.Bundle {
+	items := make([]bundle.Item, 0, len(pb.Items))
+	for _, item := range pb.Items {
+		items = append(items, bundle.Item{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return bundle.Bundle{
+		StoreID: storeID,
+		Name:    pb.Name,
+		Items:   items,
+	}
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "encoding/json"
+
+type PutExchangeRatesReq struct {
+	Rates map[string]json.Number `json:"rates" binding:"required"`
+}
+
+func (pr *PutExchangeRatesReq) ConvertToSvc() map[string]string {
+	rates := make(map[string]string, len(pr.Rates))
+	for currency, rate := range pr.Rates {
+		rates[currency] = rate.String()
+	}
+	return rates
+}
@@ -1,30 +1,56 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
 )
 
 type PostOfferReq struct {
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	StoreID   uint      `json:"store_id"`
-	Price     float64   `json:"price"`
-	Status    string    `json:"status"`
-	ExpiresAt time.Time `json:"expires_at"`
+	UserID    uint        `json:"user_id"`
+	ProductID *uint       `json:"product_id,omitempty"`
+	VariantID *uint       `json:"variant_id,omitempty"`
+	BundleID  *uint       `json:"bundle_id,omitempty"`
+	StoreID   uint        `json:"store_id"`
+	Price     money.Money `json:"price"`
+	Currency  string      `json:"currency,omitempty"`
+	Status    string      `json:"status"`
+	ExpiresAt time.Time   `json:"expires_at"`
 }
 
+// PostOfferResp describes the created offer, its store and expiration are
+// only known once the service has filled them in.
 type PostOfferResp struct {
-	ID uint `json:"id"`
+	ID        uint        `json:"id"`
+	StoreID   uint        `json:"store_id"`
+	Price     money.Money `json:"price"`
+	Currency  string      `json:"currency"`
+	Status    string      `json:"status"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+func ConvertOfferToPostResp(o entity.Offer) PostOfferResp {
+	return PostOfferResp{
+		ID:        o.ID,
+		StoreID:   o.StoreID,
+		Price:     o.Price,
+		Currency:  o.Currency,
+		Status:    o.Status,
+		ExpiresAt: o.ExpiresAt,
+	}
 }
 
 func (po *PostOfferReq) ConvertToSvc() offer.Offer {
 	return offer.Offer{
 		UserID:    po.UserID,
 		ProductID: po.ProductID,
+		VariantID: po.VariantID,
+		BundleID:  po.BundleID,
 		StoreID:   po.StoreID,
-		Price:     po.Price,
+		Price:     po.Price.WithCurrency(strings.ToUpper(po.Currency)),
 		Status:    po.Status,
 		ExpiresAt: po.ExpiresAt,
 	}
@@ -33,3 +59,14 @@ func (po *PostOfferReq) ConvertToSvc() offer.Offer {
 type PatchOfferStatusReq struct {
 	Status string `json:"status" binding:"required"`
 }
+
+type PostCounterOfferReq struct {
+	Price    money.Money `json:"price"`
+	Currency string      `json:"currency,omitempty"`
+}
+
+// CounterPrice returns the countered price, one without a currency is in the
+// store currency.
+func (pc *PostCounterOfferReq) CounterPrice() money.Money {
+	return pc.Price.WithCurrency(strings.ToUpper(pc.Currency))
+}
@@ -1,14 +1,25 @@
 package dto
 
-import "github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+import (
+	"strings"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
 
 type PostProductReq struct {
-	StoreID     uint    `json:"store_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
-	InStock     bool    `json:"in_stock"`
+	StoreID     uint        `json:"store_id"`
+	ExternalSKU *string     `json:"external_sku,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	Currency    string      `json:"currency,omitempty"`
+	Category    string      `json:"category"`
+	Quantity    int         `json:"quantity" binding:"gte=0"`
+	InStock     *bool       `json:"in_stock,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	PublishAt   *time.Time  `json:"publish_at,omitempty"`
 }
 
 type PostProductResp struct {
@@ -16,32 +27,130 @@ type PostProductResp struct {
 }
 
 func (pp *PostProductReq) ConvertToSvc() product.Product {
+	quantity := pp.Quantity
+	if quantity == 0 && pp.InStock != nil {
+		quantity = inStockQuantity(*pp.InStock)
+	}
+
 	return product.Product{
 		StoreID:     pp.StoreID,
+		ExternalSKU: pp.ExternalSKU,
 		Name:        pp.Name,
 		Description: pp.Description,
-		Price:       pp.Price,
+		Price:       pp.Price.WithCurrency(strings.ToUpper(pp.Currency)),
 		Category:    pp.Category,
-		InStock:     pp.InStock,
+		Quantity:    quantity,
+		Status:      pp.Status,
+		PublishAt:   pp.PublishAt,
 	}
 }
 
 type PatchProductReq struct {
-	StoreID     *uint    `json:"store_id,omitempty"`
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	Category    *string  `json:"category,omitempty"`
-	InStock     *bool    `json:"in_stock,omitempty"`
+	StoreID     *uint        `json:"store_id,omitempty"`
+	ExternalSKU *string      `json:"external_sku,omitempty"`
+	Name        *string      `json:"name,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Price       *money.Money `json:"price,omitempty"`
+	Category    *string      `json:"category,omitempty"`
+	Quantity    *int         `json:"quantity,omitempty" binding:"omitempty,gte=0"`
+	InStock     *bool        `json:"in_stock,omitempty"`
+	Status      *string      `json:"status,omitempty"`
+	PublishAt   *time.Time   `json:"publish_at,omitempty"`
 }
 
 func (pp *PatchProductReq) ConvertToSvc() product.UpdateProduct {
+	quantity := pp.Quantity
+	if quantity == nil && pp.InStock != nil {
+		q := inStockQuantity(*pp.InStock)
+		quantity = &q
+	}
+
 	return product.UpdateProduct{
 		StoreID:     pp.StoreID,
+		ExternalSKU: pp.ExternalSKU,
 		Name:        pp.Name,
 		Description: pp.Description,
 		Price:       pp.Price,
 		Category:    pp.Category,
-		InStock:     pp.InStock,
+		Quantity:    quantity,
+		Status:      pp.Status,
+		PublishAt:   pp.PublishAt,
+	}
+}
+
+// inStockQuantity maps in_stock, the flag products had before their stock was
+// counted, to a quantity for requests that don't send one: an in stock
+// product holds a single unit, as the stock migration assumed.
+func inStockQuantity(inStock bool) int {
+	if inStock {
+		return 1
+	}
+	return 0
+}
+
+type PostProductVariantReq struct {
+	SKU        string            `json:"sku" binding:"required"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price" binding:"gt=0"`
+	Stock      int               `json:"stock" binding:"gte=0"`
+}
+
+type PostProductVariantResp struct {
+	ID uint `json:"id"`
+}
+
+func (pv *PostProductVariantReq) ConvertToSvc() product.ProductVariant {
+	return product.ProductVariant{
+		SKU:        pv.SKU,
+		Attributes: pv.Attributes,
+		Price:      pv.Price,
+		Stock:      pv.Stock,
 	}
 }
+
+type PatchProductVariantReq struct {
+	SKU        *string            `json:"sku,omitempty"`
+	Attributes *map[string]string `json:"attributes,omitempty"`
+	Price      *money.Money       `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Stock      *int               `json:"stock,omitempty" binding:"omitempty,gte=0"`
+}
+
+func (pv *PatchProductVariantReq) ConvertToSvc() product.UpdateProductVariant {
+	return product.UpdateProductVariant{
+		SKU:        pv.SKU,
+		Attributes: pv.Attributes,
+		Price:      pv.Price,
+		Stock:      pv.Stock,
+	}
+}
+
+type PatchProductImagesReq struct {
+	ImageIDs []uint `json:"image_ids" binding:"required"`
+}
+
+type PostAttributeDefinitionReq struct {
+	Key      string   `json:"key" binding:"required"`
+	Name     string   `json:"name" binding:"required"`
+	Type     string   `json:"type" binding:"required"`
+	Options  []string `json:"options,omitempty"`
+	Position int      `json:"position"`
+}
+
+type PostAttributeDefinitionResp struct {
+	ID uint `json:"id"`
+}
+
+func (pa *PostAttributeDefinitionReq) ConvertToSvc(category string) product.AttributeDefinition {
+	return product.AttributeDefinition{
+		Category: category,
+		Key:      pa.Key,
+		Name:     pa.Name,
+		Type:     pa.Type,
+		Options:  pa.Options,
+		Position: pa.Position,
+	}
+}
+
+type PutProductAttributesReq struct {
+	Attributes map[string]interface{} `json:"attributes" binding:"required"`
+}
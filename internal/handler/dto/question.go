@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/PosokhovVadim/stawberry/internal/domain/service/question"
+
+type PostQuestionReq struct {
+	Question string `json:"question" binding:"required"`
+}
+
+func (pq *PostQuestionReq) ConvertToSvc(userID, productID uint) question.Question {
+	return question.Question{
+		ProductID: productID,
+		UserID:    userID,
+		Text:      pq.Question,
+	}
+}
+
+type PutAnswerReq struct {
+	Answer string `json:"answer" binding:"required"`
+}
+
+type PatchQuestionReq struct {
+	Question *string `json:"question,omitempty"`
+	Answer   *string `json:"answer,omitempty"`
+	Hidden   *bool   `json:"hidden,omitempty"`
+}
+
+func (pq *PatchQuestionReq) ConvertToSvc() question.Moderation {
+	return question.Moderation{
+		Question: pq.Question,
+		Answer:   pq.Answer,
+		Hidden:   pq.Hidden,
+	}
+}
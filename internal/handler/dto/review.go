@@ -0,0 +1,17 @@
+package dto
+
+import "github.com/PosokhovVadim/stawberry/internal/domain/service/review"
+
+type PostReviewReq struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text"`
+}
+
+func (pr *PostReviewReq) ConvertToSvc(userID, productID uint) review.Review {
+	return review.Review{
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    pr.Rating,
+		Text:      pr.Text,
+	}
+}
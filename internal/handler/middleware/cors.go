@@ -13,9 +13,10 @@ func CORS() gin.HandlerFunc {
 			"Content-Type, Content-Length,"+
 				" Accept-Encoding, X-CSRF-Token,"+
 				" Authorization, accept, origin,"+
-				" Cache-Control, X-Requested-With",
+				" Cache-Control, X-Requested-With, If-Match",
 		)
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -6,18 +6,27 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
 
 	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
 	"github.com/gin-gonic/gin"
 )
 
 type OfferService interface {
-	CreateOffer(offer offer.Offer) (uint, error)
+	CreateOffer(offer offer.Offer) (entity.Offer, error)
 	GetUserOffers(userID uint, limit, offset int) ([]entity.Offer, int64, error)
+	GetStoreOffers(
+		userID, storeID uint,
+		filter offer.StoreOfferFilter,
+		sort string,
+		offset, limit int,
+	) ([]entity.Offer, int64, map[string]int64, error)
 	GetOffer(offerID uint) (entity.Offer, error)
-	UpdateOfferStatus(offerID uint, status string) (entity.Offer, error)
+	UpdateOfferStatus(userID, offerID uint, status string) (entity.Offer, error)
+	CounterOffer(userID, offerID uint, price money.Money) (entity.Offer, error)
 	DeleteOffer(offerID uint) (entity.Offer, error)
 }
 
@@ -40,11 +49,9 @@ func (h *offerHandler) PostOffer(c *gin.Context) {
 
 	offer.UserID = userID.(uint)
 	offer.Status = "pending"
-	offer.ExpiresAt = time.Now().Add(24 * time.Hour)
 
-	var response dto.PostOfferResp
-	var err error
-	if response.ID, err = h.offerService.CreateOffer(offer.ConvertToSvc()); err != nil {
+	created, err := h.offerService.CreateOffer(offer.ConvertToSvc())
+	if err != nil {
 		handleOfferError(c, err)
 		return
 	}
@@ -57,7 +64,7 @@ func (h *offerHandler) PostOffer(c *gin.Context) {
 	// }
 	// h.notifyRepo.Create(&notification)
 
-	c.JSON(http.StatusCreated, offer)
+	c.JSON(http.StatusCreated, dto.ConvertOfferToPostResp(created))
 }
 
 func (h *offerHandler) GetUserOffers(c *gin.Context) {
@@ -119,6 +126,11 @@ func (h *offerHandler) GetOffer(c *gin.Context) {
 }
 
 func (h *offerHandler) PatchOfferStatus(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid nondigit offer id"})
@@ -132,7 +144,7 @@ func (h *offerHandler) PatchOfferStatus(c *gin.Context) {
 		return
 	}
 
-	offer, err := h.offerService.UpdateOfferStatus(uint(id), req.Status)
+	offer, err := h.offerService.UpdateOfferStatus(userID, uint(id), req.Status)
 	if err != nil {
 		handleOfferError(c, err)
 		return
@@ -149,6 +161,42 @@ func (h *offerHandler) PatchOfferStatus(c *gin.Context) {
 	c.JSON(http.StatusCreated, offer)
 }
 
+// PostCounterOffer answers the offer with another price, the next round of its
+// negotiation thread.
+func (h *offerHandler) PostCounterOffer(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit offer id",
+		})
+		return
+	}
+
+	var req dto.PostCounterOfferReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid counter-offer",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	offer, err := h.offerService.CounterOffer(userID, uint(id), req.CounterPrice())
+	if err != nil {
+		handleOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, offer)
+}
+
 func (h *offerHandler) DeleteOffer(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -171,3 +219,136 @@ func (h *offerHandler) DeleteOffer(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, offer)
 }
+
+// GetStoreOffers is the inbox of the offers made to a store, for its staff.
+// The meta carries the number of offers in every status for the inbox badges.
+func (h *offerHandler) GetStoreOffers(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit store id",
+		})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return
+	}
+
+	filter, ok := parseStoreOfferFilter(c)
+	if !ok {
+		return
+	}
+
+	offers, total, counts, err := h.offerService.GetStoreOffers(
+		userID,
+		uint(storeID),
+		filter,
+		c.DefaultQuery("sort", offer.SortNewest),
+		(page-1)*limit,
+		limit,
+	)
+	if err != nil {
+		handleOfferError(c, err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": offers,
+		"meta": gin.H{
+			"current_page":  page,
+			"per_page":      limit,
+			"total_items":   total,
+			"total_pages":   totalPages,
+			"status_counts": counts,
+		},
+	})
+}
+
+// parseStoreOfferFilter reads the inbox filters from the query string or
+// responds with 400. Prices are decimal amounts the service reads in the store
+// currency, creation dates are RFC 3339 timestamps.
+func parseStoreOfferFilter(c *gin.Context) (offer.StoreOfferFilter, bool) {
+	filter := offer.StoreOfferFilter{Status: c.Query("status")}
+
+	if value, ok := c.GetQuery("product_id"); ok {
+		productID, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid non digit product id",
+			})
+			return offer.StoreOfferFilter{}, false
+		}
+		id := uint(productID)
+		filter.ProductID = &id
+	}
+
+	for _, bound := range []struct {
+		param string
+		dst   **money.Money
+	}{
+		{param: "min_price", dst: &filter.MinPrice},
+		{param: "max_price", dst: &filter.MaxPrice},
+	} {
+		value, ok := c.GetQuery(bound.param)
+		if !ok {
+			continue
+		}
+		price, err := money.Parse(value, "")
+		if err != nil || price.IsNegative() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid " + bound.param + " value",
+			})
+			return offer.StoreOfferFilter{}, false
+		}
+		*bound.dst = &price
+	}
+
+	for _, bound := range []struct {
+		param string
+		dst   **time.Time
+	}{
+		{param: "created_after", dst: &filter.CreatedAfter},
+		{param: "created_before", dst: &filter.CreatedBefore},
+	} {
+		value, ok := c.GetQuery(bound.param)
+		if !ok {
+			continue
+		}
+		createdAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid " + bound.param + " value (should be an RFC 3339 timestamp)",
+			})
+			return offer.StoreOfferFilter{}, false
+		}
+		*bound.dst = &createdAt
+	}
+
+	return filter, true
+}
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"context"
+	"io"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
@@ -11,15 +15,46 @@ import (
 	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
 
 	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
 	"github.com/gin-gonic/gin"
 )
 
 type ProductService interface {
-	CreateProduct(product product.Product) (uint, error)
-	GetProductByID(id string) (entity.Product, error)
-	GetProducts(offset, limit int) ([]entity.Product, int, error)
-	GetStoreProducts(id string, offset, limit int) ([]entity.Product, int, error)
-	UpdateProduct(id string, updateProduct product.UpdateProduct) error
+	CreateProduct(product product.Product, userID uint) (uint, error)
+	GetProductByID(id string, actorID *uint) (entity.Product, error)
+	GetProductBySlug(slug string, actorID *uint) (entity.Product, error)
+	GetStoreIDBySlug(slug string) (uint, error)
+	GetProducts(filter product.ProductFilter, displayCurrency string, offset, limit int) ([]entity.Product, int, error)
+	GetStoreProducts(
+		id string,
+		filter product.ProductFilter,
+		displayCurrency string,
+		offset, limit int,
+		actorID *uint,
+	) ([]entity.Product, int, error)
+	UpdateProduct(id string, updateProduct product.UpdateProduct, ifMatch []int, userID uint) (int, error)
+	GetPriceHistory(productID string, offset, limit int) ([]entity.PriceChange, int, error)
+	ArchiveProduct(id string, userID uint) error
+	RestoreProduct(id string, userID uint) error
+	CreateProductVariant(productID uint, variant product.ProductVariant, userID uint) (uint, error)
+	UpdateProductVariant(productID, variantID uint, update product.UpdateProductVariant, userID uint) error
+	DeleteProductVariant(productID, variantID, userID uint) error
+	UploadProductImage(
+		ctx context.Context,
+		productID uint,
+		file io.Reader,
+		size int64,
+		userID uint,
+	) (entity.ProductImage, error)
+	ReorderProductImages(productID uint, imageIDs []uint, userID uint) error
+	SetPrimaryProductImage(productID, imageID, userID uint) error
+	DeleteProductImage(ctx context.Context, productID, imageID, userID uint) error
+	CreateAttributeDefinition(userID uint, definition product.AttributeDefinition) (uint, error)
+	GetAttributeDefinitions(category string) ([]entity.AttributeDefinition, error)
+	DeleteAttributeDefinition(userID uint, category string, attributeID uint) error
+	SetProductAttributes(productID uint, values map[string]interface{}, userID uint) error
+	GetProductFacets(storeID string, filter product.ProductFilter, actorID *uint) ([]entity.Facet, error)
+	GetRelatedProducts(id, displayCurrency string, limit int) ([]entity.Product, error)
 }
 
 type productHandler struct {
@@ -31,6 +66,11 @@ func NewProductHandler(productService ProductService) productHandler {
 }
 
 func (h *productHandler) PostProduct(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
 	var postProductReq dto.PostProductReq
 
 	if err := c.ShouldBindJSON(&postProductReq); err != nil {
@@ -44,7 +84,7 @@ func (h *productHandler) PostProduct(c *gin.Context) {
 
 	var response dto.PostProductResp
 	var err error
-	if response.ID, err = h.productService.CreateProduct(postProductReq.ConvertToSvc()); err != nil {
+	if response.ID, err = h.productService.CreateProduct(postProductReq.ConvertToSvc(), userID); err != nil {
 		handleProductError(c, err)
 		return
 	}
@@ -53,14 +93,42 @@ func (h *productHandler) PostProduct(c *gin.Context) {
 }
 
 func (h *productHandler) GetProduct(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := numericIDParam(c, "product")
+	if !ok {
+		return
+	}
 
-	product, err := h.productService.GetProductByID(id)
+	product, err := h.productService.GetProductByID(id, actorID(c))
 	if err != nil {
 		handleProductError(c, err)
 		return
 	}
 
+	c.Header("ETag", productETag(product.Version))
+	c.JSON(http.StatusOK, product)
+}
+
+// GetProductBySlug responds with the product or, for a slug the product had
+// before a rename, redirects to its current slug.
+func (h *productHandler) GetProductBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	product, err := h.productService.GetProductBySlug(slug, actorID(c))
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	if product.Slug != slug {
+		location := strings.TrimSuffix(c.Request.URL.Path, slug) + product.Slug
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	c.Header("ETag", productETag(product.Version))
 	c.JSON(http.StatusOK, product)
 }
 
@@ -83,9 +151,28 @@ func (h *productHandler) GetProducts(c *gin.Context) {
 		return
 	}
 
+	filter, ok := parseProductFilter(c)
+	if !ok {
+		return
+	}
+
+	displayCurrency, ok := parseDisplayCurrency(c)
+	if !ok {
+		return
+	}
+
 	offset := (page - 1) * limit
 
-	products, total, err := h.productService.GetProducts(offset, limit)
+	products, total, err := h.productService.GetProducts(filter, displayCurrency, offset, limit)
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	// The global listing only shows published products whatever the status
+	// asked for, its facets count the same products
+	filter.Status = product.StatusPublished
+	facets, err := h.productService.GetProductFacets("", filter, actorID(c))
 	if err != nil {
 		handleProductError(c, err)
 		return
@@ -94,7 +181,8 @@ func (h *productHandler) GetProducts(c *gin.Context) {
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": products,
+		"data":   products,
+		"facets": facets,
 		"meta": gin.H{
 			"current_page": page,
 			"per_page":     limit,
@@ -105,8 +193,25 @@ func (h *productHandler) GetProducts(c *gin.Context) {
 }
 
 func (h *productHandler) GetStoreProducts(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := numericIDParam(c, "store")
+	if !ok {
+		return
+	}
+
+	h.respondStoreProducts(c, id)
+}
+
+func (h *productHandler) GetStoreProductsBySlug(c *gin.Context) {
+	storeID, err := h.productService.GetStoreIDBySlug(c.Param("slug"))
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	h.respondStoreProducts(c, strconv.FormatUint(uint64(storeID), 10))
+}
 
+func (h *productHandler) respondStoreProducts(c *gin.Context, id string) {
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -125,9 +230,25 @@ func (h *productHandler) GetStoreProducts(c *gin.Context) {
 		return
 	}
 
+	filter, ok := parseProductFilter(c)
+	if !ok {
+		return
+	}
+
+	displayCurrency, ok := parseDisplayCurrency(c)
+	if !ok {
+		return
+	}
+
 	offset := (page - 1) * limit
 
-	products, total, err := h.productService.GetStoreProducts(id, offset, limit)
+	products, total, err := h.productService.GetStoreProducts(id, filter, displayCurrency, offset, limit, actorID(c))
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	facets, err := h.productService.GetProductFacets(id, filter, actorID(c))
 	if err != nil {
 		handleProductError(c, err)
 		return
@@ -136,7 +257,8 @@ func (h *productHandler) GetStoreProducts(c *gin.Context) {
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": products,
+		"data":   products,
+		"facets": facets,
 		"meta": gin.H{
 			"current_page": page,
 			"per_page":     limit,
@@ -147,7 +269,15 @@ func (h *productHandler) GetStoreProducts(c *gin.Context) {
 }
 
 func (h *productHandler) PatchProduct(c *gin.Context) {
-	id := c.Param("id")
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := numericIDParam(c, "product")
+	if !ok {
+		return
+	}
 
 	var update dto.PatchProductReq
 	if err := c.ShouldBindJSON(&update); err != nil {
@@ -159,10 +289,314 @@ func (h *productHandler) PatchProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.productService.UpdateProduct(id, update.ConvertToSvc()); err != nil {
+	ifMatch, ok := parseIfMatch(c.GetHeader("If-Match"))
+	if !ok {
+		handleProductError(c, apperror.ErrProductVersionMismatch)
+		return
+	}
+
+	version, err := h.productService.UpdateProduct(id, update.ConvertToSvc(), ifMatch, userID)
+	if err != nil {
 		handleProductError(c, err)
 		return
 	}
 
+	c.Header("ETag", productETag(version))
 	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
 }
+
+func (h *productHandler) GetRelatedProducts(c *gin.Context) {
+	id, ok := numericIDParam(c, "product")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 20 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 20)",
+		})
+		return
+	}
+
+	displayCurrency, ok := parseDisplayCurrency(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.productService.GetRelatedProducts(id, displayCurrency, limit)
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": products})
+}
+
+func (h *productHandler) GetPriceHistory(c *gin.Context) {
+	id, ok := numericIDParam(c, "product")
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return
+	}
+
+	offset := (page - 1) * limit
+
+	history, total, err := h.productService.GetPriceHistory(id, offset, limit)
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": history,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+func (h *productHandler) DeleteProduct(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := numericIDParam(c, "product")
+	if !ok {
+		return
+	}
+
+	if err := h.productService.ArchiveProduct(id, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product archived successfully"})
+}
+
+func (h *productHandler) RestoreProduct(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := numericIDParam(c, "product")
+	if !ok {
+		return
+	}
+
+	if err := h.productService.RestoreProduct(id, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product restored successfully"})
+}
+
+// numericIDParam returns the id route parameter once it is known to be a
+// number, otherwise it responds with 400.
+func numericIDParam(c *gin.Context, name string) (string, bool) {
+	id := c.Param("id")
+	if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit " + name + " id",
+		})
+		return "", false
+	}
+	return id, true
+}
+
+// parseProductFilter reads the listing filters from the query string or
+// responds with 400. Price bounds are in the ?currency= the buyer browses in,
+// or in the default currency.
+func parseProductFilter(c *gin.Context) (product.ProductFilter, bool) {
+	filter := product.ProductFilter{Category: c.Query("category")}
+
+	priceCurrency := strings.ToUpper(c.Query("currency"))
+	if !money.ValidCurrency(priceCurrency) {
+		priceCurrency = money.DefaultCurrency
+	}
+
+	for _, bound := range []struct {
+		param string
+		dst   **money.Money
+	}{
+		{param: "min_price", dst: &filter.MinPrice},
+		{param: "max_price", dst: &filter.MaxPrice},
+	} {
+		value, ok := c.GetQuery(bound.param)
+		if !ok {
+			continue
+		}
+		price, err := money.Parse(value, priceCurrency)
+		if err != nil || price.IsNegative() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid " + bound.param + " value",
+			})
+			return product.ProductFilter{}, false
+		}
+		*bound.dst = &price
+	}
+
+	attributes, ok := parseAttributeFilters(c)
+	if !ok {
+		return product.ProductFilter{}, false
+	}
+	filter.Attributes = attributes
+
+	if value, ok := c.GetQuery("in_stock"); ok {
+		inStock, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid in_stock value",
+			})
+			return product.ProductFilter{}, false
+		}
+		filter.InStock = &inStock
+	}
+
+	switch filter.Status = c.Query("status"); filter.Status {
+	case "", product.StatusDraft, product.StatusScheduled, product.StatusPublished:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid status value (should be draft, scheduled or published)",
+		})
+		return product.ProductFilter{}, false
+	}
+
+	return filter, true
+}
+
+// parseDisplayCurrency reads the ?currency= listing parameter or responds with 400.
+func parseDisplayCurrency(c *gin.Context) (string, bool) {
+	displayCurrency := strings.ToUpper(c.Query("currency"))
+	if displayCurrency != "" && !money.ValidCurrency(displayCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid currency code",
+		})
+		return "", false
+	}
+	return displayCurrency, true
+}
+
+// parseAttributeFilters reads attr.<key>=v1,v2 filters and, for numeric
+// attributes, attr.<key>.min and attr.<key>.max bounds.
+func parseAttributeFilters(c *gin.Context) ([]product.AttributeFilter, bool) {
+	byKey := make(map[string]*product.AttributeFilter)
+	var keys []string
+
+	for param, values := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, "attr.")
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		var bound string
+		if base, found := strings.CutSuffix(key, ".min"); found {
+			key, bound = base, "min"
+		} else if base, found := strings.CutSuffix(key, ".max"); found {
+			key, bound = base, "max"
+		}
+		if key == "" {
+			continue
+		}
+
+		filter, ok := byKey[key]
+		if !ok {
+			filter = &product.AttributeFilter{Key: key}
+			byKey[key] = filter
+			keys = append(keys, key)
+		}
+
+		if bound == "" {
+			for _, v := range strings.Split(values[0], ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					filter.Values = append(filter.Values, v)
+				}
+			}
+			continue
+		}
+
+		number, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid " + param + " value",
+			})
+			return nil, false
+		}
+		if bound == "min" {
+			filter.Min = &number
+		} else {
+			filter.Max = &number
+		}
+	}
+
+	// Query parameters come from a map, sort them for stable queries
+	sort.Strings(keys)
+	filters := make([]product.AttributeFilter, 0, len(keys))
+	for _, key := range keys {
+		filters = append(filters, *byKey[key])
+	}
+
+	return filters, true
+}
+
+func productETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// parseIfMatch returns the product versions listed in an If-Match header, nil
+// when the header is absent or "*". If-Match uses strong comparison, so weak
+// or malformed tags never match and ok is false when none is left.
+func parseIfMatch(header string) ([]int, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	var versions []int
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil || !strings.HasPrefix(tag, `"`) {
+			continue
+		}
+		version, err := strconv.Atoi(unquoted)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, len(versions) > 0
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *productHandler) GetCategoryAttributes(c *gin.Context) {
+	definitions, err := h.productService.GetAttributeDefinitions(c.Param("category"))
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, definitions)
+}
+
+func (h *productHandler) PostCategoryAttribute(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostAttributeDefinitionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid attribute data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var response dto.PostAttributeDefinitionResp
+	var err error
+	if response.ID, err = h.productService.CreateAttributeDefinition(
+		userID, req.ConvertToSvc(c.Param("category")),
+	); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *productHandler) DeleteCategoryAttribute(c *gin.Context) {
+	attributeID, err := strconv.Atoi(c.Param("attributeID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit attribute id",
+		})
+		return
+	}
+
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteAttributeDefinition(userID, c.Param("category"), uint(attributeID)); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute deleted successfully"})
+}
+
+func (h *productHandler) PutProductAttributes(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutProductAttributesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product attributes",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.productService.SetProductAttributes(uint(productID), req.Attributes, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product attributes updated successfully"})
+}
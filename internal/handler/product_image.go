@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *productHandler) PostProductImage(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Image file is required",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid image file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	image, err := h.productService.UploadProductImage(
+		c.Request.Context(),
+		uint(productID),
+		file,
+		fileHeader.Size,
+		userID,
+	)
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+func (h *productHandler) PatchProductImages(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	var req dto.PatchProductImagesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid image order",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.productService.ReorderProductImages(uint(productID), req.ImageIDs, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product images reordered successfully"})
+}
+
+func (h *productHandler) PutPrimaryProductImage(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, imageID, ok := parseImageParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.SetPrimaryProductImage(productID, imageID, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Primary product image updated successfully"})
+}
+
+func (h *productHandler) DeleteProductImage(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, imageID, ok := parseImageParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteProductImage(c.Request.Context(), productID, imageID, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product image deleted successfully"})
+}
+
+func parseImageParams(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return 0, 0, false
+	}
+
+	imageID, err := strconv.Atoi(c.Param("imageID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit image id",
+		})
+		return 0, 0, false
+	}
+
+	return uint(productID), uint(imageID), true
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *productHandler) PostProductVariant(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	var req dto.PostProductVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid variant data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var response dto.PostProductVariantResp
+	if response.ID, err = h.productService.CreateProductVariant(uint(productID), req.ConvertToSvc(), userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *productHandler) PatchProductVariant(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, variantID, ok := parseVariantParams(c)
+	if !ok {
+		return
+	}
+
+	var update dto.PatchProductVariantReq
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid update data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.productService.UpdateProductVariant(productID, variantID, update.ConvertToSvc(), userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product variant updated successfully"})
+}
+
+func (h *productHandler) DeleteProductVariant(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, variantID, ok := parseVariantParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteProductVariant(productID, variantID, userID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product variant deleted successfully"})
+}
+
+func parseVariantParams(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return 0, 0, false
+	}
+
+	variantID, err := strconv.Atoi(c.Param("variantID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit variant id",
+		})
+		return 0, 0, false
+	}
+
+	return uint(productID), uint(variantID), true
+}
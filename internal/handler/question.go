@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/question"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionService interface {
+	AskQuestion(question question.Question) (entity.ProductQuestion, error)
+	AnswerQuestion(userID, questionID uint, answer string) (entity.ProductQuestion, error)
+	GetProductQuestions(productID uint, offset, limit int) ([]entity.ProductQuestion, int, error)
+	GetStoreQuestions(userID, storeID uint, answered *bool, offset, limit int) ([]entity.ProductQuestion, int, error)
+	ModerateQuestion(userID, questionID uint, moderation question.Moderation) (entity.ProductQuestion, error)
+}
+
+type questionHandler struct {
+	questionService QuestionService
+}
+
+func NewQuestionHandler(questionService QuestionService) questionHandler {
+	return questionHandler{questionService: questionService}
+}
+
+func (h *questionHandler) GetProductQuestions(c *gin.Context) {
+	productID, ok := questionIDParam(c, "product")
+	if !ok {
+		return
+	}
+
+	page, limit, ok := questionPage(c)
+	if !ok {
+		return
+	}
+
+	questions, total, err := h.questionService.GetProductQuestions(productID, (page-1)*limit, limit)
+	if err != nil {
+		handleQuestionError(c, err)
+		return
+	}
+
+	respondQuestions(c, questions, total, page, limit)
+}
+
+func (h *questionHandler) PostProductQuestion(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, ok := questionIDParam(c, "product")
+	if !ok {
+		return
+	}
+
+	var req dto.PostQuestionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid question",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	q, err := h.questionService.AskQuestion(req.ConvertToSvc(userID, productID))
+	if err != nil {
+		handleQuestionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, q)
+}
+
+func (h *questionHandler) PutQuestionAnswer(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	questionID, ok := questionIDParam(c, "question")
+	if !ok {
+		return
+	}
+
+	var req dto.PutAnswerReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid answer",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	q, err := h.questionService.AnswerQuestion(userID, questionID, req.Answer)
+	if err != nil {
+		handleQuestionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, q)
+}
+
+func (h *questionHandler) GetStoreQuestions(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	storeID, ok := questionIDParam(c, "store")
+	if !ok {
+		return
+	}
+
+	page, limit, ok := questionPage(c)
+	if !ok {
+		return
+	}
+
+	var answered *bool
+	if value := c.Query("answered"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid answered value (should be true or false)",
+			})
+			return
+		}
+		answered = &parsed
+	}
+
+	questions, total, err := h.questionService.GetStoreQuestions(userID, storeID, answered, (page-1)*limit, limit)
+	if err != nil {
+		handleQuestionError(c, err)
+		return
+	}
+
+	respondQuestions(c, questions, total, page, limit)
+}
+
+func (h *questionHandler) PatchQuestion(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	questionID, ok := questionIDParam(c, "question")
+	if !ok {
+		return
+	}
+
+	var req dto.PatchQuestionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid moderation data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	q, err := h.questionService.ModerateQuestion(userID, questionID, req.ConvertToSvc())
+	if err != nil {
+		handleQuestionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, q)
+}
+
+func questionIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit " + name + " id",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func questionPage(c *gin.Context) (int, int, bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return 0, 0, false
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return 0, 0, false
+	}
+
+	return page, limit, true
+}
+
+func respondQuestions(c *gin.Context, questions []entity.ProductQuestion, total, page, limit int) {
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": questions,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
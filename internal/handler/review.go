@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/review"
+	"github.com/PosokhovVadim/stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewService interface {
+	CreateReview(review review.Review) (entity.ProductReview, error)
+	GetProductReviews(
+		ctx context.Context,
+		productID uint,
+		sort string,
+		offset, limit int,
+	) ([]entity.ProductReview, int, error)
+	DeleteReview(ctx context.Context, userID, reviewID uint) error
+	UploadReviewPhoto(ctx context.Context, userID, reviewID uint, file io.Reader, size int64) (entity.ReviewPhoto, error)
+	DeleteReviewPhoto(ctx context.Context, userID, reviewID, photoID uint) error
+}
+
+type reviewHandler struct {
+	reviewService ReviewService
+}
+
+func NewReviewHandler(reviewService ReviewService) reviewHandler {
+	return reviewHandler{reviewService: reviewService}
+}
+
+func (h *reviewHandler) GetProductReviews(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return
+	}
+
+	sort := c.DefaultQuery("sort", review.SortNewest)
+
+	reviews, total, err := h.reviewService.GetProductReviews(
+		c.Request.Context(),
+		uint(productID),
+		sort,
+		(page-1)*limit,
+		limit,
+	)
+	if err != nil {
+		handleReviewError(c, err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reviews,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+func (h *reviewHandler) PostProductReview(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit product id",
+		})
+		return
+	}
+
+	var req dto.PostReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid review",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rv, err := h.reviewService.CreateReview(req.ConvertToSvc(userID, uint(productID)))
+	if err != nil {
+		handleReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rv)
+}
+
+func (h *reviewHandler) DeleteReview(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit review id",
+		})
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(c.Request.Context(), userID, uint(reviewID)); err != nil {
+		handleReviewError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *reviewHandler) PostReviewPhoto(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit review id",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Photo file is required",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid photo file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	photo, err := h.reviewService.UploadReviewPhoto(
+		c.Request.Context(),
+		userID,
+		uint(reviewID),
+		file,
+		fileHeader.Size,
+	)
+	if err != nil {
+		handleReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+func (h *reviewHandler) DeleteReviewPhoto(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit review id",
+		})
+		return
+	}
+
+	photoID, err := strconv.Atoi(c.Param("photoID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit photo id",
+		})
+		return
+	}
+
+	if err := h.reviewService.DeleteReviewPhoto(c.Request.Context(), userID, uint(reviewID), uint(photoID)); err != nil {
+		handleReviewError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"reflect"
+
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// registerValidators lets binding tags such as gt=0 validate money amounts by
+// their minor units.
+func registerValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if m, ok := field.Interface().(money.Money); ok {
+			return m.Amount
+		}
+		return nil
+	}, money.Money{})
+}
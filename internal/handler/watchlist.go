@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistService interface {
+	WatchProduct(userID, productID uint) error
+	UnwatchProduct(userID, productID uint) error
+	WatchStore(userID, storeID uint) error
+	UnwatchStore(userID, storeID uint) error
+	GetWatchlist(userID uint, itemType string, offset, limit int) ([]entity.WatchlistItem, int, error)
+}
+
+type watchlistHandler struct {
+	watchlistService WatchlistService
+}
+
+func NewWatchlistHandler(watchlistService WatchlistService) watchlistHandler {
+	return watchlistHandler{watchlistService: watchlistService}
+}
+
+func (h *watchlistHandler) GetWatchlist(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return
+	}
+
+	offset := (page - 1) * limit
+
+	items, total, err := h.watchlistService.GetWatchlist(userID, c.Query("type"), offset, limit)
+	if err != nil {
+		handleWatchlistError(c, err)
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": items,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+func (h *watchlistHandler) PostWatchedProduct(c *gin.Context) {
+	h.updateWatchlist(c, "product", h.watchlistService.WatchProduct, http.StatusCreated)
+}
+
+func (h *watchlistHandler) DeleteWatchedProduct(c *gin.Context) {
+	h.updateWatchlist(c, "product", h.watchlistService.UnwatchProduct, http.StatusNoContent)
+}
+
+func (h *watchlistHandler) PostWatchedStore(c *gin.Context) {
+	h.updateWatchlist(c, "store", h.watchlistService.WatchStore, http.StatusCreated)
+}
+
+func (h *watchlistHandler) DeleteWatchedStore(c *gin.Context) {
+	h.updateWatchlist(c, "store", h.watchlistService.UnwatchStore, http.StatusNoContent)
+}
+
+// updateWatchlist adds or removes the product or store given by the id param.
+func (h *watchlistHandler) updateWatchlist(
+	c *gin.Context,
+	itemType string,
+	update func(userID, itemID uint) error,
+	status int,
+) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid non digit " + itemType + " id",
+		})
+		return
+	}
+
+	if err := update(userID, uint(itemID)); err != nil {
+		handleWatchlistError(c, err)
+		return
+	}
+
+	c.Status(status)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/alert"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type alertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) *alertRepository {
+	return &alertRepository{db: db}
+}
+
+// UpsertPriceAlert creates the alert or replaces the target of the existing
+// alert of the user on the product, arming it in both cases.
+func (r *alertRepository) UpsertPriceAlert(priceAlert alert.PriceAlert) (entity.PriceAlert, error) {
+	alertModel := model.ConvertPriceAlertFromSvc(priceAlert)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var productModel model.Product
+		if err := tx.Scopes(activeProducts, publishedProducts).
+			Select("id", "currency").
+			Where("id = ?", priceAlert.ProductID).
+			First(&productModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrAlertProductNotFound
+			}
+			return &apperror.AlertError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch alert product",
+				Err:     err,
+			}
+		}
+
+		// Targets are always in the product currency
+		alertModel.Currency = productModel.Currency
+		alertModel.TargetPrice = alertModel.TargetPrice.WithCurrency(productModel.Currency)
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"target_price": alertModel.TargetPrice,
+				"currency":     alertModel.Currency,
+				"triggered_at": nil,
+				"updated_at":   time.Now(),
+			}),
+		}).Create(&alertModel).Error; err != nil {
+			return &apperror.AlertError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to save price alert",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.PriceAlert{}, err
+	}
+
+	return r.getPriceAlert(priceAlert.UserID, alertModel.ID)
+}
+
+func (r *alertRepository) RearmPriceAlert(userID, alertID uint) (entity.PriceAlert, error) {
+	result := r.db.Model(&model.PriceAlert{}).
+		Where("id = ? AND user_id = ?", alertID, userID).
+		Update("triggered_at", nil)
+	if result.Error != nil {
+		return entity.PriceAlert{}, &apperror.AlertError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to re-arm price alert",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return entity.PriceAlert{}, apperror.ErrAlertNotFound
+	}
+
+	return r.getPriceAlert(userID, alertID)
+}
+
+func (r *alertRepository) DeletePriceAlert(userID, alertID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", alertID, userID).Delete(&model.PriceAlert{})
+	if result.Error != nil {
+		return &apperror.AlertError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to delete price alert",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+func (r *alertRepository) SelectUserPriceAlerts(userID uint, offset, limit int) ([]entity.PriceAlert, int, error) {
+	var total int64
+	if err := r.db.Model(&model.PriceAlert{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, &apperror.AlertError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count price alerts",
+			Err:     err,
+		}
+	}
+
+	var alertModels []model.PriceAlert
+	if err := r.db.Preload("Product").
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&alertModels).Error; err != nil {
+		return nil, 0, &apperror.AlertError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch price alerts",
+			Err:     err,
+		}
+	}
+
+	alerts := make([]entity.PriceAlert, 0, len(alertModels))
+	for _, a := range alertModels {
+		alerts = append(alerts, model.ConvertPriceAlertToEntity(a))
+	}
+
+	return alerts, int(total), nil
+}
+
+func (r *alertRepository) getPriceAlert(userID, alertID uint) (entity.PriceAlert, error) {
+	var alertModel model.PriceAlert
+	if err := r.db.Preload("Product").
+		Where("id = ? AND user_id = ?", alertID, userID).
+		First(&alertModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PriceAlert{}, apperror.ErrAlertNotFound
+		}
+		return entity.PriceAlert{}, &apperror.AlertError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch price alert",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertPriceAlertToEntity(alertModel), nil
+}
+
+// firePriceAlerts notifies every user whose armed alert on the product has a
+// target at or above the new price, then disarms those alerts so each fires
+// once until it is re-armed.
+func firePriceAlerts(tx *gorm.DB, productID uint, productName string, price money.Money) error {
+	var alertModels []model.PriceAlert
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND triggered_at IS NULL", productID).
+		Where("currency = ? AND target_price >= ?", price.Currency, price).
+		Find(&alertModels).Error; err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch price alerts",
+			Err:     err,
+		}
+	}
+	if len(alertModels) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(alertModels))
+	notifications := make([]model.Notification, 0, len(alertModels))
+	for _, a := range alertModels {
+		ids = append(ids, a.ID)
+		notifications = append(notifications, model.Notification{
+			UserID:    a.UserID,
+			Type:      model.NotificationPriceAlert,
+			ProductID: &a.ProductID,
+			Message: fmt.Sprintf(
+				"The price of %s dropped to %s, at or below your target of %s",
+				productName, price, a.TargetPrice.WithCurrency(a.Currency),
+			),
+		})
+	}
+
+	if err := tx.Create(&notifications).Error; err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to create price alert notifications",
+			Err:     err,
+		}
+	}
+
+	if err := tx.Model(&model.PriceAlert{}).Where("id IN ?", ids).Update("triggered_at", time.Now()).Error; err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to disarm price alerts",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/bundle"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bundleRepository struct {
+	db *gorm.DB
+}
+
+func NewBundleRepository(db *gorm.DB) *bundleRepository {
+	return &bundleRepository{db: db}
+}
+
+// InsertBundle stores the bundle once every item is an active, published
+// product (or one of its variants) of the same store. A bundle without a
+// store belongs to the store selling its products.
+func (r *bundleRepository) InsertBundle(b bundle.Bundle) (uint, error) {
+	bundleModel := model.ConvertBundleFromSvc(b)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		productIDs := make([]uint, 0, len(b.Items))
+		variantIDs := make([]uint, 0, len(b.Items))
+		for _, item := range b.Items {
+			productIDs = append(productIDs, item.ProductID)
+			if item.VariantID != nil {
+				variantIDs = append(variantIDs, *item.VariantID)
+			}
+		}
+
+		// FOR SHARE keeps the products from being archived until the bundle is in
+		var productModels []model.Product
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Scopes(activeProducts, publishedProducts).
+			Select("id", "store_id").
+			Where("id IN ?", productIDs).
+			Find(&productModels).Error; err != nil {
+			return &apperror.BundleError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch bundle products",
+				Err:     err,
+			}
+		}
+
+		stores := make(map[uint]uint, len(productModels))
+		for _, p := range productModels {
+			stores[p.ID] = p.StoreID
+		}
+		for _, item := range b.Items {
+			storeID, ok := stores[item.ProductID]
+			if !ok {
+				return apperror.ErrBundleProductNotFound
+			}
+			if bundleModel.StoreID == 0 {
+				bundleModel.StoreID = storeID
+			}
+			if storeID != bundleModel.StoreID {
+				return apperror.ErrBundleStoreMismatch
+			}
+		}
+
+		if err := checkBundleVariants(tx, b.Items, variantIDs); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&bundleModel).Error; err != nil {
+			return &apperror.BundleError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create bundle",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return bundleModel.ID, nil
+}
+
+func (r *bundleRepository) GetBundle(bundleID uint) (entity.Bundle, error) {
+	var bundleModel model.Bundle
+	if err := r.db.Scopes(preloadBundleItems).
+		Where("id = ?", bundleID).
+		First(&bundleModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Bundle{}, apperror.ErrBundleNotFound
+		}
+		return entity.Bundle{}, &apperror.BundleError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch bundle",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertBundleToEntity(bundleModel), nil
+}
+
+// SelectStoreBundles returns the bundles defined by the store, buyer bundles
+// are left out.
+func (r *bundleRepository) SelectStoreBundles(storeID uint, offset, limit int) ([]entity.Bundle, int, error) {
+	var total int64
+	if err := r.db.Model(&model.Bundle{}).
+		Where("store_id = ? AND user_id IS NULL", storeID).
+		Count(&total).Error; err != nil {
+		return nil, 0, &apperror.BundleError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count bundles",
+			Err:     err,
+		}
+	}
+
+	var bundleModels []model.Bundle
+	if err := r.db.Scopes(preloadBundleItems).
+		Where("store_id = ? AND user_id IS NULL", storeID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&bundleModels).Error; err != nil {
+		return nil, 0, &apperror.BundleError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch bundles",
+			Err:     err,
+		}
+	}
+
+	bundles := make([]entity.Bundle, 0, len(bundleModels))
+	for _, b := range bundleModels {
+		bundles = append(bundles, model.ConvertBundleToEntity(b))
+	}
+
+	return bundles, int(total), nil
+}
+
+// checkBundleVariants returns ErrBundleVariantMismatch unless every variant
+// of the items belongs to the item product.
+func checkBundleVariants(tx *gorm.DB, items []bundle.Item, variantIDs []uint) error {
+	if len(variantIDs) == 0 {
+		return nil
+	}
+
+	var variantModels []model.ProductVariant
+	if err := tx.Select("id", "product_id").
+		Where("id IN ?", variantIDs).
+		Find(&variantModels).Error; err != nil {
+		return &apperror.BundleError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch bundle variants",
+			Err:     err,
+		}
+	}
+
+	products := make(map[uint]uint, len(variantModels))
+	for _, v := range variantModels {
+		products[v.ID] = v.ProductID
+	}
+	for _, item := range items {
+		if item.VariantID != nil && products[*item.VariantID] != item.ProductID {
+			return apperror.ErrBundleVariantMismatch
+		}
+	}
+
+	return nil
+}
+
+// preloadBundleItems loads the items with their product name and unit price,
+// the variant price when the item is a variant.
+func preloadBundleItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Select(`bundle_items.*, products.name,
+			COALESCE(product_variants.price, products.price) AS unit_price, products.currency`).
+			Joins("JOIN products ON products.id = bundle_items.product_id").
+			Joins("LEFT JOIN product_variants ON product_variants.id = bundle_items.variant_id").
+			Order("bundle_items.id")
+	})
+}
+
+// bundleItems returns the items of a bundle in a stable order, so that the
+// stock of a bundle is always locked in the same order.
+func bundleItems(tx *gorm.DB, bundleID uint) ([]model.BundleItem, error) {
+	var items []model.BundleItem
+	if err := tx.Select("bundle_id", "product_id", "variant_id", "quantity").
+		Where("bundle_id = ?", bundleID).
+		Order("product_id, variant_id").
+		Find(&items).Error; err != nil {
+		return nil, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch bundle items",
+			Err:     err,
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/catalog"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) *catalogRepository {
+	return &catalogRepository{db: db}
+}
+
+func (r *catalogRepository) InsertImport(catalogImport catalog.Import) (uint, error) {
+	importModel := model.ConvertCatalogImportFromSvc(catalogImport)
+	if err := r.db.Create(&importModel).Error; err != nil {
+		if isForeignKeyError(err) {
+			return 0, &apperror.CatalogError{
+				Code:    apperror.NotFound,
+				Message: "store not found",
+				Err:     err,
+			}
+		}
+		return 0, &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to create catalog import",
+			Err:     err,
+		}
+	}
+
+	return importModel.ID, nil
+}
+
+func (r *catalogRepository) GetImport(storeID, importID uint) (entity.CatalogImport, error) {
+	var importModel model.CatalogImport
+	if err := r.db.Where("id = ? AND store_id = ?", importID, storeID).First(&importModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.CatalogImport{}, apperror.ErrCatalogImportNotFound
+		}
+		return entity.CatalogImport{}, &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch catalog import",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertCatalogImportToEntity(importModel), nil
+}
+
+// ClaimPendingImports marks up to limit pending imports as processing and
+// returns them. Imports left in processing for longer than staleAfter are
+// claimed again. Rows locked by another replica are skipped.
+func (r *catalogRepository) ClaimPendingImports(limit int, staleAfter time.Duration) ([]entity.CatalogImport, error) {
+	now := time.Now()
+
+	var importModels []model.CatalogImport
+	if err := r.db.Raw(`
+		UPDATE catalog_imports
+		SET status = ?, claimed_at = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM catalog_imports
+			WHERE status = ? OR (status = ? AND claimed_at < ?)
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		catalog.ImportProcessing, now, now,
+		catalog.ImportPending, catalog.ImportProcessing, now.Add(-staleAfter),
+		limit,
+	).Scan(&importModels).Error; err != nil {
+		return nil, &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to claim pending catalog imports",
+			Err:     err,
+		}
+	}
+
+	imports := make([]entity.CatalogImport, 0, len(importModels))
+	for _, i := range importModels {
+		imports = append(imports, model.ConvertCatalogImportToEntity(i))
+	}
+
+	return imports, nil
+}
+
+// ResetImportProgress clears the counters and row errors left by an earlier
+// attempt and records the number of rows in the file.
+func (r *catalogRepository) ResetImportProgress(importID uint, total int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("import_id = ?", importID).Delete(&model.CatalogImportError{}).Error; err != nil {
+			return &apperror.CatalogError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to clear catalog import errors",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Model(&model.CatalogImport{ID: importID}).Updates(map[string]interface{}{
+			"total_rows":     total,
+			"processed_rows": 0,
+			"succeeded_rows": 0,
+			"failed_rows":    0,
+			"error":          nil,
+		}).Error; err != nil {
+			return &apperror.CatalogError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to reset catalog import progress",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpsertCatalogRows creates or updates the store products matching the rows
+// by external SKU in one transaction. Every row runs in its own savepoint, so
+// a row rejected by the database is reported without losing the others.
+func (r *catalogRepository) UpsertCatalogRows(
+	storeID uint,
+	rows []catalog.Row,
+	actorID *uint,
+) ([]catalog.RowError, error) {
+	var rowErrors []catalog.RowError
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		rowErrors = nil
+		defaultCurrency, err := storeCurrency(tx, storeID)
+		if err != nil {
+			return err
+		}
+
+		for i, row := range rows {
+			savepoint := fmt.Sprintf("catalog_row_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return &apperror.CatalogError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to create savepoint",
+					Err:     err,
+				}
+			}
+
+			if err := upsertCatalogRow(tx, storeID, defaultCurrency, row, actorID); err != nil {
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return &apperror.CatalogError{
+						Code:    apperror.DatabaseError,
+						Message: "failed to roll back catalog row",
+						Err:     rbErr,
+					}
+				}
+				rowErrors = append(rowErrors, catalog.RowError{
+					Line:        row.Line,
+					ExternalSKU: row.ExternalSKU,
+					Message:     err.Error(),
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rowErrors, nil
+}
+
+// upsertCatalogRow creates or updates the product of a row. Rows without a
+// currency keep the product currency, new products default to the store one.
+func upsertCatalogRow(tx *gorm.DB, storeID uint, defaultCurrency string, row catalog.Row, actorID *uint) error {
+	var productModel model.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("store_id = ? AND external_sku = ?", storeID, row.ExternalSKU).
+		First(&productModel).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		currency := row.Currency
+		if currency == "" {
+			currency = defaultCurrency
+		}
+
+		sku := row.ExternalSKU
+		productModel = model.Product{
+			StoreID:     storeID,
+			ExternalSKU: &sku,
+			Name:        row.Name,
+			Description: row.Description,
+			Price:       row.Price.WithCurrency(currency),
+			Currency:    currency,
+			Category:    row.Category,
+			Quantity:    row.Quantity,
+			// The column defaults to published for products that predate
+			// drafts, imported ones wait for the store to publish them
+			Status: product.StatusDraft,
+		}
+		if productModel.Slug, err = uniqueProductSlug(tx, 0, row.Name); err != nil {
+			return err
+		}
+		if err := tx.Create(&productModel).Error; err != nil {
+			return errors.New("failed to create product")
+		}
+		return insertPriceChange(tx, productModel.ID, nil, productModel.Price, actorID)
+	}
+	if err != nil {
+		return errors.New("failed to fetch product")
+	}
+
+	currency := row.Currency
+	if currency == "" {
+		currency = productModel.Currency
+	}
+	oldPrice := productModel.Price.WithCurrency(productModel.Currency)
+	newPrice := row.Price.WithCurrency(currency)
+	newSlug := productModel.Slug
+	if row.Name != productModel.Name {
+		if newSlug, err = renameProductSlug(tx, productModel.ID, productModel.Slug, row.Name); err != nil {
+			return err
+		}
+	}
+	if err := tx.Model(&productModel).Updates(map[string]interface{}{
+		"slug":        newSlug,
+		"name":        row.Name,
+		"description": row.Description,
+		"price":       newPrice,
+		"currency":    currency,
+		"category":    row.Category,
+		"quantity":    row.Quantity,
+		"version":     gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return errors.New("failed to update product")
+	}
+
+	if oldPrice.Currency != newPrice.Currency || oldPrice.Cmp(newPrice) != 0 {
+		if err := insertPriceChange(tx, productModel.ID, &oldPrice, newPrice, actorID); err != nil {
+			return err
+		}
+	}
+
+	if oldPrice.Currency == newPrice.Currency && newPrice.Cmp(oldPrice) < 0 {
+		return firePriceAlerts(tx, productModel.ID, row.Name, newPrice)
+	}
+
+	return nil
+}
+
+// UpdateImportProgress stores the progress counters and appends the row errors
+// of the last processed chunk.
+func (r *catalogRepository) UpdateImportProgress(
+	importID uint,
+	progress catalog.Progress,
+	rowErrors []catalog.RowError,
+) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(rowErrors) > 0 {
+			errorModels := make([]model.CatalogImportError, 0, len(rowErrors))
+			for _, e := range rowErrors {
+				errorModels = append(errorModels, model.ConvertCatalogImportErrorFromSvc(importID, e))
+			}
+			if err := tx.Create(&errorModels).Error; err != nil {
+				return &apperror.CatalogError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to save catalog import errors",
+					Err:     err,
+				}
+			}
+		}
+
+		if err := tx.Model(&model.CatalogImport{ID: importID}).Updates(map[string]interface{}{
+			"processed_rows": progress.Processed,
+			"succeeded_rows": progress.Succeeded,
+			"failed_rows":    progress.Failed,
+		}).Error; err != nil {
+			return &apperror.CatalogError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to update catalog import progress",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetImportStatus moves the import to status. Completed and failed imports
+// are stamped with their finish time.
+func (r *catalogRepository) SetImportStatus(importID uint, status string, errMsg *string) error {
+	updates := map[string]interface{}{
+		"status": status,
+		"error":  errMsg,
+	}
+	if status == catalog.ImportCompleted || status == catalog.ImportFailed {
+		updates["finished_at"] = time.Now()
+	}
+
+	if err := r.db.Model(&model.CatalogImport{ID: importID}).Updates(updates).Error; err != nil {
+		return &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update catalog import status",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (r *catalogRepository) SelectImportErrors(importID uint) ([]entity.CatalogImportError, error) {
+	var errorModels []model.CatalogImportError
+	if err := r.db.Where("import_id = ?", importID).Order("row_number, id").Find(&errorModels).Error; err != nil {
+		return nil, &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch catalog import errors",
+			Err:     err,
+		}
+	}
+
+	rowErrors := make([]entity.CatalogImportError, 0, len(errorModels))
+	for _, e := range errorModels {
+		rowErrors = append(rowErrors, model.ConvertCatalogImportErrorToEntity(e))
+	}
+
+	return rowErrors, nil
+}
+
+// StreamStoreProducts reads the active store products matching the filter
+// from a database cursor and passes them to fn one at a time.
+func (r *catalogRepository) StreamStoreProducts(
+	ctx context.Context,
+	storeID uint,
+	filter product.ProductFilter,
+	fn func(entity.Product) error,
+) error {
+	rows, err := r.db.WithContext(ctx).
+		Model(&model.Product{}).
+		Scopes(activeProducts, filterProducts(filter)).
+		Where("store_id = ?", storeID).
+		Order("id").
+		Rows()
+	if err != nil {
+		return &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to query store products",
+			Err:     err,
+		}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productModel model.Product
+		if err := r.db.ScanRows(rows, &productModel); err != nil {
+			return &apperror.CatalogError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to read store product",
+				Err:     err,
+			}
+		}
+		if err := fn(model.ConvertProductToEntity(productModel)); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return &apperror.CatalogError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to read store products",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type currencyRepository struct {
+	db *gorm.DB
+}
+
+func NewCurrencyRepository(db *gorm.DB) *currencyRepository {
+	return &currencyRepository{db: db}
+}
+
+func (r *currencyRepository) SelectExchangeRates() ([]entity.ExchangeRate, error) {
+	var rateModels []model.ExchangeRate
+	if err := r.db.Order("currency").Find(&rateModels).Error; err != nil {
+		return nil, &apperror.CurrencyError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch exchange rates",
+			Err:     err,
+		}
+	}
+
+	rates := make([]entity.ExchangeRate, 0, len(rateModels))
+	for _, rate := range rateModels {
+		rates = append(rates, model.ConvertExchangeRateToEntity(rate))
+	}
+
+	return rates, nil
+}
+
+func (r *currencyRepository) UpsertExchangeRates(rates []currency.Rate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rateModels := make([]model.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		rateModel := model.ConvertExchangeRateFromSvc(rate)
+		rateModel.UpdatedAt = now
+		rateModels = append(rateModels, rateModel)
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&rateModels).Error; err != nil {
+		return &apperror.CurrencyError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save exchange rates",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// inBaseCurrency converts an amount to money.DefaultCurrency through the
+// exchange rates so prices of different currencies can be compared in SQL.
+// It is NULL when there is no rate for the currency, so such prices never
+// match a price filter and sort after the others.
+func inBaseCurrency(amount, currency string) string {
+	return fmt.Sprintf(
+		"(%s * CASE WHEN %s = '%s' THEN 1 ELSE (SELECT rate FROM exchange_rates WHERE exchange_rates.currency = %s) END)",
+		amount, currency, money.DefaultCurrency, currency,
+	)
+}
@@ -20,8 +20,30 @@ func InitDB(cfg *config.Config) *gorm.DB {
 	err = db.AutoMigrate(
 		&model.User{},
 		&model.Store{},
+		&model.StoreMember{},
 		&model.Product{},
+		&model.ProductSlugRedirect{},
+		&model.ProductVariant{},
+		&model.CategoryAttribute{},
+		&model.ProductAttributeValue{},
+		&model.ProductImage{},
+		&model.ProductImageRendition{},
+		&model.ProductPriceHistory{},
+		&model.RelatedProduct{},
+		&model.RelatedProductRefresh{},
+		&model.Bundle{},
+		&model.BundleItem{},
 		&model.Offer{},
+		&model.OfferRound{},
+		&model.StockReservation{},
+		&model.CatalogImport{},
+		&model.CatalogImportError{},
+		&model.ExchangeRate{},
+		&model.WatchlistItem{},
+		&model.PriceAlert{},
+		&model.ProductQuestion{},
+		&model.ProductReview{},
+		&model.ProductReviewPhoto{},
 		&model.Notification{},
 	)
 	if err != nil {
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/alert"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type PriceAlert struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	UserID      uint `gorm:"not null;uniqueIndex:idx_price_alerts_user_product"`
+	ProductID   uint `gorm:"not null;uniqueIndex:idx_price_alerts_user_product"`
+	TargetPrice money.Money
+	Currency    string
+	TriggeredAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Product     Product `gorm:"foreignKey:ProductID"`
+}
+
+func ConvertPriceAlertFromSvc(a alert.PriceAlert) PriceAlert {
+	return PriceAlert{
+		UserID:      a.UserID,
+		ProductID:   a.ProductID,
+		TargetPrice: a.TargetPrice,
+	}
+}
+
+func ConvertPriceAlertToEntity(a PriceAlert) entity.PriceAlert {
+	return entity.PriceAlert{
+		ID:           a.ID,
+		ProductID:    a.ProductID,
+		ProductName:  a.Product.Name,
+		TargetPrice:  a.TargetPrice.WithCurrency(a.Currency),
+		CurrentPrice: a.Product.Price.WithCurrency(a.Product.Currency),
+		Currency:     a.Currency,
+		Armed:        a.TriggeredAt == nil,
+		TriggeredAt:  a.TriggeredAt,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/bundle"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type Bundle struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	StoreID   uint `gorm:"not null"`
+	UserID    *uint
+	Name      string       `gorm:"not null"`
+	Items     []BundleItem `gorm:"foreignKey:BundleID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type BundleItem struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	BundleID  uint `gorm:"not null"`
+	ProductID uint `gorm:"not null"`
+	VariantID *uint
+	Quantity  int         `gorm:"not null"`
+	Name      string      `gorm:"->;-:migration"`
+	UnitPrice money.Money `gorm:"->;-:migration"`
+	Currency  string      `gorm:"->;-:migration"`
+}
+
+func ConvertBundleFromSvc(b bundle.Bundle) Bundle {
+	items := make([]BundleItem, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, BundleItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return Bundle{
+		StoreID: b.StoreID,
+		UserID:  b.UserID,
+		Name:    b.Name,
+		Items:   items,
+	}
+}
+
+func ConvertBundleToEntity(b Bundle) entity.Bundle {
+	items := make([]entity.BundleItem, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, entity.BundleItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice.WithCurrency(item.Currency),
+		})
+	}
+
+	return entity.Bundle{
+		ID:        b.ID,
+		StoreID:   b.StoreID,
+		UserID:    b.UserID,
+		Name:      b.Name,
+		Items:     items,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
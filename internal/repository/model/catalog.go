@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/catalog"
+)
+
+type CatalogImport struct {
+	ID            uint `gorm:"primaryKey;autoIncrement"`
+	StoreID       uint
+	UserID        *uint
+	Format        string
+	ObjectKey     string
+	Status        string `gorm:"default:pending"`
+	TotalRows     int
+	ProcessedRows int
+	SucceededRows int
+	FailedRows    int
+	Error         *string
+	Attempts      int
+	ClaimedAt     *time.Time
+	FinishedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type CatalogImportError struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	ImportID    uint
+	RowNumber   int
+	ExternalSKU string
+	Message     string
+}
+
+func ConvertCatalogImportFromSvc(i catalog.Import) CatalogImport {
+	return CatalogImport{
+		ID:        i.ID,
+		StoreID:   i.StoreID,
+		UserID:    i.UserID,
+		Format:    i.Format,
+		ObjectKey: i.ObjectKey,
+		Status:    i.Status,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func ConvertCatalogImportToEntity(i CatalogImport) entity.CatalogImport {
+	return entity.CatalogImport{
+		ID:            i.ID,
+		StoreID:       i.StoreID,
+		UserID:        i.UserID,
+		Format:        i.Format,
+		ObjectKey:     i.ObjectKey,
+		Status:        i.Status,
+		TotalRows:     i.TotalRows,
+		ProcessedRows: i.ProcessedRows,
+		SucceededRows: i.SucceededRows,
+		FailedRows:    i.FailedRows,
+		Error:         i.Error,
+		Attempts:      i.Attempts,
+		FinishedAt:    i.FinishedAt,
+		CreatedAt:     i.CreatedAt,
+		UpdatedAt:     i.UpdatedAt,
+	}
+}
+
+func ConvertCatalogImportErrorFromSvc(importID uint, e catalog.RowError) CatalogImportError {
+	return CatalogImportError{
+		ImportID:    importID,
+		RowNumber:   e.Line,
+		ExternalSKU: e.ExternalSKU,
+		Message:     e.Message,
+	}
+}
+
+func ConvertCatalogImportErrorToEntity(e CatalogImportError) entity.CatalogImportError {
+	return entity.CatalogImportError{
+		Line:        e.RowNumber,
+		ExternalSKU: e.ExternalSKU,
+		Message:     e.Message,
+	}
+}
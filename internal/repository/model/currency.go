@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/currency"
+)
+
+type ExchangeRate struct {
+	Currency  string `gorm:"primaryKey"`
+	Rate      string `gorm:"type:numeric(20,10)"`
+	UpdatedAt time.Time
+}
+
+func ConvertExchangeRateFromSvc(r currency.Rate) ExchangeRate {
+	return ExchangeRate{
+		Currency: r.Currency,
+		Rate:     r.Rate.FloatString(10),
+	}
+}
+
+func ConvertExchangeRateToEntity(r ExchangeRate) entity.ExchangeRate {
+	return entity.ExchangeRate{
+		Currency:  r.Currency,
+		Rate:      r.Rate,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
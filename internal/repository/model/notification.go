@@ -2,11 +2,21 @@ package model
 
 import "time"
 
+const (
+	NotificationOffer      = "offer"
+	NotificationPriceAlert = "price_alert"
+	NotificationQuestion   = "question"
+	NotificationAnswer     = "answer"
+)
+
 type Notification struct {
-	ID        uint `gorm:"primaryKey"`
-	UserID    uint
-	OfferID   uint
-	Message   string
-	Read      bool
-	CreatedAt time.Time
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint
+	Type       string `gorm:"not null;default:offer"`
+	OfferID    *uint
+	ProductID  *uint
+	QuestionID *uint
+	Message    string
+	Read       bool
+	CreatedAt  time.Time
 }
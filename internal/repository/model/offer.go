@@ -3,21 +3,39 @@ package model
 import (
 	"time"
 
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
 )
 
 type Offer struct {
 	ID        uint `gorm:"primaryKey;autoIncrement"`
 	UserID    uint
-	ProductID uint
+	ProductID *uint
+	VariantID *uint
+	BundleID  *uint
 	StoreID   uint
-	Price     float64
+	Price     money.Money
+	Currency  string
 	Status    string
+	MaxRounds int
 	ExpiresAt time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Product   Product `gorm:"foreignKey:ProductID"`
-	Store     Store   `gorm:"foreignKey:StoreID"`
+	Product   Product      `gorm:"foreignKey:ProductID"`
+	Store     Store        `gorm:"foreignKey:StoreID"`
+	Rounds    []OfferRound `gorm:"foreignKey:OfferID"`
+}
+
+type OfferRound struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	OfferID   uint `gorm:"not null"`
+	Round     int  `gorm:"not null"`
+	UserID    *uint
+	Side      string `gorm:"not null"`
+	Price     money.Money
+	Currency  string
+	CreatedAt time.Time
 }
 
 func ConvertOfferFromSvc(offer offer.Offer) Offer {
@@ -25,11 +43,65 @@ func ConvertOfferFromSvc(offer offer.Offer) Offer {
 		ID:        offer.ID,
 		UserID:    offer.UserID,
 		ProductID: offer.ProductID,
+		VariantID: offer.VariantID,
+		BundleID:  offer.BundleID,
 		StoreID:   offer.StoreID,
 		Price:     offer.Price,
+		Currency:  offer.Price.Currency,
 		Status:    offer.Status,
+		MaxRounds: offer.MaxRounds,
 		ExpiresAt: offer.ExpiresAt,
 		CreatedAt: offer.CreatedAt,
 		UpdatedAt: offer.UpdatedAt,
 	}
 }
+
+func ConvertOfferToEntity(o Offer) entity.Offer {
+	return entity.Offer{
+		ID:        o.ID,
+		UserID:    o.UserID,
+		ProductID: o.ProductID,
+		VariantID: o.VariantID,
+		BundleID:  o.BundleID,
+		StoreID:   o.StoreID,
+		Price:     o.Price.WithCurrency(o.Currency),
+		Currency:  o.Currency,
+		Status:    o.Status,
+		MaxRounds: o.MaxRounds,
+		Rounds:    convertOfferRoundsToEntity(o.Rounds),
+		ExpiresAt: o.ExpiresAt,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+func ConvertOfferRoundFromSvc(r offer.Round) OfferRound {
+	return OfferRound{
+		OfferID:  r.OfferID,
+		UserID:   &r.UserID,
+		Side:     r.Side,
+		Price:    r.Price,
+		Currency: r.Price.Currency,
+	}
+}
+
+func convertOfferRoundsToEntity(rounds []OfferRound) []entity.OfferRound {
+	if len(rounds) == 0 {
+		return nil
+	}
+
+	result := make([]entity.OfferRound, 0, len(rounds))
+	for _, r := range rounds {
+		result = append(result, entity.OfferRound{
+			ID:        r.ID,
+			OfferID:   r.OfferID,
+			Round:     r.Round,
+			UserID:    r.UserID,
+			Side:      r.Side,
+			Price:     r.Price.WithCurrency(r.Currency),
+			CreatedAt: r.CreatedAt,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type ProductPriceHistory struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	ProductID uint
+	OldPrice  *money.Money
+	NewPrice  money.Money
+	ChangedBy *uint
+	ChangedAt time.Time
+}
+
+func (ProductPriceHistory) TableName() string {
+	return "product_price_history"
+}
+
+func ConvertPriceHistoryToEntity(h ProductPriceHistory) entity.PriceChange {
+	return entity.PriceChange{
+		ID:        h.ID,
+		ProductID: h.ProductID,
+		OldPrice:  h.OldPrice,
+		NewPrice:  h.NewPrice,
+		ChangedBy: h.ChangedBy,
+		ChangedAt: h.ChangedAt,
+	}
+}
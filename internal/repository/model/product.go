@@ -5,65 +5,163 @@ import (
 
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
 )
 
 type Product struct {
-	ID          uint `gorm:"primaryKey;autoIncrement"`
-	StoreID     uint
-	Name        string
-	Description string
-	Price       float64
-	Category    string
-	InStock     bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Store       Store `gorm:"foreignKey:StoreID"`
+	ID              uint `gorm:"primaryKey;autoIncrement"`
+	StoreID         uint
+	ExternalSKU     *string `gorm:"column:external_sku"`
+	Slug            string  `gorm:"unique"`
+	Name            string
+	Description     string
+	Price           money.Money
+	Currency        string
+	Category        string
+	Quantity        int
+	Status          string `gorm:"not null;default:published"`
+	PublishAt       *time.Time
+	InStock         bool        `gorm:"->;-:migration"`
+	LowestPrice30d  money.Money `gorm:"column:lowest_price_30d;->;-:migration"`
+	WatcherCount    int         `gorm:"->;-:migration"`
+	RatingAverage   float64     `gorm:"->;-:migration"`
+	ReviewCount     int         `gorm:"->;-:migration"`
+	ArchivedAt      *time.Time
+	Version         int `gorm:"not null;default:1"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Store           Store                   `gorm:"foreignKey:StoreID"`
+	Variants        []ProductVariant        `gorm:"foreignKey:ProductID"`
+	AttributeValues []ProductAttributeValue `gorm:"foreignKey:ProductID"`
+	Images          []ProductImage          `gorm:"foreignKey:ProductID"`
 }
 
 type UpdateProduct struct {
-	StoreID     *uint    `gorm:"column:store_id"`
-	Name        *string  `gorm:"column:name"`
-	Description *string  `gorm:"column:description"`
-	Price       *float64 `gorm:"column:price"`
-	Category    *string  `gorm:"column:category"`
-	InStock     *bool    `gorm:"column:in_stock"`
+	StoreID     *uint        `gorm:"column:store_id"`
+	ExternalSKU *string      `gorm:"column:external_sku"`
+	Name        *string      `gorm:"column:name"`
+	Description *string      `gorm:"column:description"`
+	Price       *money.Money `gorm:"column:price"`
+	Category    *string      `gorm:"column:category"`
+	Quantity    *int         `gorm:"column:quantity"`
+	Status      *string      `gorm:"column:status"`
+	PublishAt   *time.Time   `gorm:"column:publish_at"`
 }
 
 func ConvertProductFromSvc(p product.Product) Product {
 	return Product{
 		ID:          p.ID,
 		StoreID:     p.StoreID,
+		ExternalSKU: p.ExternalSKU,
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
+		Currency:    p.Price.Currency,
 		Category:    p.Category,
-		InStock:     p.InStock,
+		Quantity:    p.Quantity,
+		Status:      p.Status,
+		PublishAt:   p.PublishAt,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
 	}
 }
 
 func ConvertProductToEntity(p Product) entity.Product {
+	var variants []entity.ProductVariant
+	if len(p.Variants) > 0 {
+		variants = make([]entity.ProductVariant, 0, len(p.Variants))
+		for _, v := range p.Variants {
+			variant := ConvertProductVariantToEntity(v)
+			variant.Price = variant.Price.WithCurrency(p.Currency)
+			variants = append(variants, variant)
+		}
+	}
+
+	var images []entity.ProductImage
+	if len(p.Images) > 0 {
+		images = make([]entity.ProductImage, 0, len(p.Images))
+		for _, i := range p.Images {
+			images = append(images, ConvertProductImageToEntity(i))
+		}
+	}
+
 	return entity.Product{
-		ID:          p.ID,
-		StoreID:     p.StoreID,
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Category:    p.Category,
-		InStock:     p.InStock,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		ID:             p.ID,
+		StoreID:        p.StoreID,
+		ExternalSKU:    p.ExternalSKU,
+		Slug:           p.Slug,
+		Name:           p.Name,
+		Description:    p.Description,
+		Price:          p.Price.WithCurrency(p.Currency),
+		Currency:       p.Currency,
+		LowestPrice30d: p.LowestPrice30d.WithCurrency(p.Currency),
+		Category:       p.Category,
+		Quantity:       p.Quantity,
+		InStock:        p.Quantity > 0,
+		Status:         p.Status,
+		PublishAt:      p.PublishAt,
+		WatcherCount:   p.WatcherCount,
+		RatingAverage:  p.RatingAverage,
+		ReviewCount:    p.ReviewCount,
+		ArchivedAt:     p.ArchivedAt,
+		Version:        p.Version,
+		Attributes:     ConvertAttributeValuesToEntity(p.AttributeValues),
+		Variants:       variants,
+		Images:         images,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
 	}
 }
 
 func ConvertUpdateProductFromSvc(up product.UpdateProduct) UpdateProduct {
 	return UpdateProduct{
 		StoreID:     up.StoreID,
+		ExternalSKU: up.ExternalSKU,
 		Name:        up.Name,
 		Description: up.Description,
 		Price:       up.Price,
 		Category:    up.Category,
-		InStock:     up.InStock,
+		Quantity:    up.Quantity,
+		Status:      up.Status,
+		PublishAt:   up.PublishAt,
 	}
 }
+
+// Columns returns the columns set by the update, nil fields are left out.
+// The publication time always changes together with the status.
+func (up UpdateProduct) Columns() map[string]interface{} {
+	columns := make(map[string]interface{})
+	if up.StoreID != nil {
+		columns["store_id"] = *up.StoreID
+	}
+	if up.ExternalSKU != nil {
+		columns["external_sku"] = *up.ExternalSKU
+	}
+	if up.Name != nil {
+		columns["name"] = *up.Name
+	}
+	if up.Description != nil {
+		columns["description"] = *up.Description
+	}
+	if up.Price != nil {
+		columns["price"] = *up.Price
+	}
+	if up.Category != nil {
+		columns["category"] = *up.Category
+	}
+	if up.Quantity != nil {
+		columns["quantity"] = *up.Quantity
+	}
+	if up.Status != nil {
+		columns["status"] = *up.Status
+		columns["publish_at"] = up.PublishAt
+	}
+	return columns
+}
+
+// ProductSlugRedirect is a slug a product had before a rename.
+type ProductSlugRedirect struct {
+	Slug      string `gorm:"primaryKey"`
+	ProductID uint   `gorm:"not null"`
+	CreatedAt time.Time
+}
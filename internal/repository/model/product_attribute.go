@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+)
+
+type CategoryAttribute struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	Category  string
+	Key       string
+	Name      string
+	Type      string
+	Options   []string `gorm:"type:jsonb;serializer:json"`
+	Position  int
+	CreatedAt time.Time
+}
+
+type ProductAttributeValue struct {
+	ProductID   uint `gorm:"primaryKey"`
+	AttributeID uint `gorm:"primaryKey"`
+	ValueText   string
+	ValueNumber *float64
+	ValueBool   *bool
+	Attribute   CategoryAttribute `gorm:"foreignKey:AttributeID"`
+}
+
+func ConvertAttributeDefinitionFromSvc(d product.AttributeDefinition) CategoryAttribute {
+	return CategoryAttribute{
+		ID:        d.ID,
+		Category:  d.Category,
+		Key:       d.Key,
+		Name:      d.Name,
+		Type:      d.Type,
+		Options:   d.Options,
+		Position:  d.Position,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+func ConvertAttributeDefinitionToEntity(a CategoryAttribute) entity.AttributeDefinition {
+	return entity.AttributeDefinition{
+		ID:        a.ID,
+		Category:  a.Category,
+		Key:       a.Key,
+		Name:      a.Name,
+		Type:      a.Type,
+		Options:   a.Options,
+		Position:  a.Position,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func ConvertAttributeValueFromSvc(v product.AttributeValue) ProductAttributeValue {
+	return ProductAttributeValue{
+		ProductID:   v.ProductID,
+		AttributeID: v.AttributeID,
+		ValueText:   v.Text,
+		ValueNumber: v.Number,
+		ValueBool:   v.Bool,
+	}
+}
+
+// ConvertAttributeValuesToEntity maps attribute keys to values of their type.
+func ConvertAttributeValuesToEntity(values []ProductAttributeValue) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]interface{}, len(values))
+	for _, v := range values {
+		switch {
+		case v.Attribute.Type == product.AttributeNumber && v.ValueNumber != nil:
+			attributes[v.Attribute.Key] = *v.ValueNumber
+		case v.Attribute.Type == product.AttributeBoolean && v.ValueBool != nil:
+			attributes[v.Attribute.Key] = *v.ValueBool
+		default:
+			attributes[v.Attribute.Key] = v.ValueText
+		}
+	}
+
+	return attributes
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+)
+
+type ProductImage struct {
+	ID               uint `gorm:"primaryKey;autoIncrement"`
+	ProductID        uint
+	ObjectKey        string `gorm:"unique"`
+	ContentType      string
+	Size             int64
+	Position         int
+	IsPrimary        bool
+	ProcessingStatus string `gorm:"default:pending"`
+	Attempts         int
+	ClaimedAt        *time.Time
+	CreatedAt        time.Time
+	Renditions       []ProductImageRendition `gorm:"foreignKey:ImageID"`
+}
+
+type ProductImageRendition struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	ImageID     uint
+	Name        string
+	ObjectKey   string `gorm:"unique"`
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+	CreatedAt   time.Time
+}
+
+func ConvertProductImageFromSvc(i product.ProductImage) ProductImage {
+	return ProductImage{
+		ID:               i.ID,
+		ProductID:        i.ProductID,
+		ObjectKey:        i.ObjectKey,
+		ContentType:      i.ContentType,
+		Size:             i.Size,
+		Position:         i.Position,
+		IsPrimary:        i.IsPrimary,
+		ProcessingStatus: i.ProcessingStatus,
+		CreatedAt:        i.CreatedAt,
+	}
+}
+
+func ConvertProductImageToEntity(i ProductImage) entity.ProductImage {
+	var renditions []entity.ProductImageRendition
+	if len(i.Renditions) > 0 {
+		renditions = make([]entity.ProductImageRendition, 0, len(i.Renditions))
+		for _, r := range i.Renditions {
+			renditions = append(renditions, entity.ProductImageRendition{
+				Name:        r.Name,
+				ObjectKey:   r.ObjectKey,
+				ContentType: r.ContentType,
+				Width:       r.Width,
+				Height:      r.Height,
+				Size:        r.Size,
+			})
+		}
+	}
+
+	return entity.ProductImage{
+		ID:          i.ID,
+		ProductID:   i.ProductID,
+		ObjectKey:   i.ObjectKey,
+		ContentType: i.ContentType,
+		Size:        i.Size,
+		Position:    i.Position,
+		IsPrimary:   i.IsPrimary,
+		Status:      i.ProcessingStatus,
+		Attempts:    i.Attempts,
+		Renditions:  renditions,
+		CreatedAt:   i.CreatedAt,
+	}
+}
+
+func ConvertProductImageRenditionFromSvc(r product.ProductImageRendition) ProductImageRendition {
+	return ProductImageRendition{
+		ImageID:     r.ImageID,
+		Name:        r.Name,
+		ObjectKey:   r.ObjectKey,
+		ContentType: r.ContentType,
+		Width:       r.Width,
+		Height:      r.Height,
+		Size:        r.Size,
+	}
+}
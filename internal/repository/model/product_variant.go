@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+)
+
+type ProductVariant struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	ProductID  uint
+	SKU        string            `gorm:"column:sku"`
+	Attributes map[string]string `gorm:"type:jsonb;serializer:json"`
+	Price      money.Money
+	Stock      int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type UpdateProductVariant struct {
+	SKU        *string            `gorm:"column:sku"`
+	Attributes *map[string]string `gorm:"column:attributes;type:jsonb;serializer:json"`
+	Price      *money.Money       `gorm:"column:price"`
+	Stock      *int               `gorm:"column:stock"`
+}
+
+func ConvertProductVariantFromSvc(v product.ProductVariant) ProductVariant {
+	return ProductVariant{
+		ID:         v.ID,
+		ProductID:  v.ProductID,
+		SKU:        v.SKU,
+		Attributes: v.Attributes,
+		Price:      v.Price,
+		Stock:      v.Stock,
+		CreatedAt:  v.CreatedAt,
+		UpdatedAt:  v.UpdatedAt,
+	}
+}
+
+func ConvertProductVariantToEntity(v ProductVariant) entity.ProductVariant {
+	return entity.ProductVariant{
+		ID:         v.ID,
+		ProductID:  v.ProductID,
+		SKU:        v.SKU,
+		Attributes: v.Attributes,
+		Price:      v.Price,
+		Stock:      v.Stock,
+		CreatedAt:  v.CreatedAt,
+		UpdatedAt:  v.UpdatedAt,
+	}
+}
+
+func ConvertUpdateProductVariantFromSvc(uv product.UpdateProductVariant) UpdateProductVariant {
+	return UpdateProductVariant{
+		SKU:        uv.SKU,
+		Attributes: uv.Attributes,
+		Price:      uv.Price,
+		Stock:      uv.Stock,
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/question"
+)
+
+type ProductQuestion struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	ProductID  uint `gorm:"not null"`
+	StoreID    uint `gorm:"->;-:migration"`
+	UserID     uint `gorm:"not null"`
+	Question   string
+	Answer     *string
+	AnsweredBy *uint
+	AnsweredAt *time.Time
+	HiddenAt   *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func ConvertQuestionFromSvc(q question.Question) ProductQuestion {
+	return ProductQuestion{
+		ProductID: q.ProductID,
+		UserID:    q.UserID,
+		Question:  q.Text,
+	}
+}
+
+func ConvertQuestionToEntity(q ProductQuestion) entity.ProductQuestion {
+	return entity.ProductQuestion{
+		ID:         q.ID,
+		ProductID:  q.ProductID,
+		StoreID:    q.StoreID,
+		UserID:     q.UserID,
+		Question:   q.Question,
+		Answer:     q.Answer,
+		AnsweredBy: q.AnsweredBy,
+		AnsweredAt: q.AnsweredAt,
+		HiddenAt:   q.HiddenAt,
+		CreatedAt:  q.CreatedAt,
+		UpdatedAt:  q.UpdatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+)
+
+type RelatedProduct struct {
+	ProductID        uint `gorm:"primaryKey;autoIncrement:false"`
+	RelatedProductID uint `gorm:"primaryKey;autoIncrement:false"`
+	Score            float64
+}
+
+type RelatedProductRefresh struct {
+	ProductID   uint `gorm:"primaryKey;autoIncrement:false"`
+	RefreshedAt time.Time
+}
+
+func ConvertProductToRelatedCandidate(p Product) product.RelatedCandidate {
+	return product.RelatedCandidate{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Category:    p.Category,
+		Price:       p.Price.WithCurrency(p.Currency),
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	ReservationActive    = "active"
+	ReservationFulfilled = "fulfilled"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+type StockReservation struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	OfferID   uint
+	ProductID uint
+	VariantID *uint
+	Quantity  int
+	Status    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
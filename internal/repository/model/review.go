@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/review"
+)
+
+type ProductReview struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	ProductID uint `gorm:"not null"`
+	UserID    uint `gorm:"not null"`
+	OfferID   uint `gorm:"not null"`
+	Rating    int  `gorm:"not null"`
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Photos    []ProductReviewPhoto `gorm:"foreignKey:ReviewID"`
+}
+
+type ProductReviewPhoto struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	ReviewID    uint
+	ObjectKey   string `gorm:"unique"`
+	ContentType string
+	Size        int64
+	Position    int
+	CreatedAt   time.Time
+}
+
+func ConvertReviewFromSvc(r review.Review) ProductReview {
+	return ProductReview{
+		ProductID: r.ProductID,
+		UserID:    r.UserID,
+		Rating:    r.Rating,
+		Text:      r.Text,
+	}
+}
+
+func ConvertReviewToEntity(r ProductReview) entity.ProductReview {
+	photos := make([]entity.ReviewPhoto, 0, len(r.Photos))
+	for _, photo := range r.Photos {
+		photos = append(photos, ConvertReviewPhotoToEntity(photo))
+	}
+
+	return entity.ProductReview{
+		ID:        r.ID,
+		ProductID: r.ProductID,
+		UserID:    r.UserID,
+		OfferID:   r.OfferID,
+		Rating:    r.Rating,
+		Text:      r.Text,
+		Photos:    photos,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func ConvertReviewPhotoFromSvc(p review.ReviewPhoto) ProductReviewPhoto {
+	return ProductReviewPhoto{
+		ReviewID:    p.ReviewID,
+		ObjectKey:   p.ObjectKey,
+		ContentType: p.ContentType,
+		Size:        p.Size,
+	}
+}
+
+func ConvertReviewPhotoToEntity(p ProductReviewPhoto) entity.ReviewPhoto {
+	return entity.ReviewPhoto{
+		ID:          p.ID,
+		ReviewID:    p.ReviewID,
+		ObjectKey:   p.ObjectKey,
+		ContentType: p.ContentType,
+		Size:        p.Size,
+		Position:    p.Position,
+		CreatedAt:   p.CreatedAt,
+	}
+}
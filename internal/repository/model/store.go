@@ -6,8 +6,10 @@ import (
 
 type Store struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
+	Slug        string    `json:"slug" gorm:"unique;not null"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Currency    string    `json:"currency" gorm:"default:RUB"`
 	Products    []Product `gorm:"foreignKey:StoreID"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
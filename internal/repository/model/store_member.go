@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type StoreMember struct {
+	StoreID   uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"primaryKey"`
+	Role      string
+	CreatedAt time.Time
+}
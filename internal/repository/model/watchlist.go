@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/watchlist"
+)
+
+type WatchlistItem struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	UserID    uint `gorm:"not null"`
+	ProductID *uint
+	StoreID   *uint
+	CreatedAt time.Time
+	Product   *Product `gorm:"foreignKey:ProductID"`
+	Store     *Store   `gorm:"foreignKey:StoreID"`
+}
+
+func ConvertWatchlistItemFromSvc(item watchlist.Item) WatchlistItem {
+	return WatchlistItem{
+		UserID:    item.UserID,
+		ProductID: item.ProductID,
+		StoreID:   item.StoreID,
+	}
+}
+
+func ConvertWatchlistItemToEntity(item WatchlistItem) entity.WatchlistItem {
+	watchlistItem := entity.WatchlistItem{
+		ID:        item.ID,
+		CreatedAt: item.CreatedAt,
+	}
+
+	if item.Product != nil {
+		p := item.Product
+		watchlistItem.Type = watchlist.ItemProduct
+		watchlistItem.Product = &entity.WatchedProduct{
+			ID:         p.ID,
+			StoreID:    p.StoreID,
+			Name:       p.Name,
+			Price:      p.Price.WithCurrency(p.Currency),
+			Currency:   p.Currency,
+			Quantity:   p.Quantity,
+			InStock:    p.Quantity > 0,
+			ArchivedAt: p.ArchivedAt,
+		}
+	}
+
+	if item.Store != nil {
+		s := item.Store
+		watchlistItem.Type = watchlist.ItemStore
+		watchlistItem.Store = &entity.WatchedStore{
+			ID:          s.ID,
+			Name:        s.Name,
+			Description: s.Description,
+			Currency:    s.Currency,
+		}
+	}
+
+	return watchlistItem
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type offerRepository struct {
@@ -20,29 +21,126 @@ func NewOfferRepository(db *gorm.DB) *offerRepository {
 	return &offerRepository{db: db}
 }
 
-func (r *offerRepository) InsertOffer(offer offer.Offer) (uint, error) {
-	offerModel := model.ConvertOfferFromSvc(offer)
-	if err := r.db.Create(&offerModel).Error; err != nil {
-		if isDuplicateError(err) {
-			return 0, &apperror.OfferError{
-				Code:    apperror.DuplicateError,
-				Message: "offer with this id already exists",
+func (r *offerRepository) InsertOffer(o offer.Offer) (uint, error) {
+	offerModel := model.ConvertOfferFromSvc(o)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if o.BundleID != nil {
+			err = checkOfferBundle(tx, *o.BundleID)
+		} else {
+			err = checkOfferProduct(tx, *o.ProductID, o.VariantID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// The offer opens its negotiation thread with the buyer price
+		offerModel.Rounds = []model.OfferRound{{
+			Round:    1,
+			UserID:   &offerModel.UserID,
+			Side:     offer.SideBuyer,
+			Price:    offerModel.Price,
+			Currency: offerModel.Currency,
+		}}
+
+		if err := tx.Create(&offerModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.OfferError{
+					Code:    apperror.DuplicateError,
+					Message: "offer with this id already exists",
+					Err:     err,
+				}
+			}
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "offer to create product",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return offerModel.ID, nil
+}
+
+// checkOfferProduct checks the offered product and variant. FOR SHARE keeps
+// the product from being archived until the offer is in.
+func checkOfferProduct(tx *gorm.DB, productID uint, variantID *uint) error {
+	var productModel model.Product
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Select("id", "archived_at").
+		Where("id = ?", productID).
+		First(&productModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.ErrOfferProductNotFound
+		}
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check offer product",
+			Err:     err,
+		}
+	}
+	if productModel.ArchivedAt != nil {
+		return apperror.ErrOfferProductArchived
+	}
+
+	if variantID != nil {
+		var count int64
+		if err := tx.Model(&model.ProductVariant{}).
+			Where("id = ? AND product_id = ?", *variantID, productID).
+			Count(&count).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to check offer variant",
 				Err:     err,
 			}
 		}
-		return 0, &apperror.OfferError{
+		if count == 0 {
+			return apperror.ErrOfferVariantMismatch
+		}
+	}
+
+	return nil
+}
+
+// checkOfferBundle checks that no product of the offered bundle is archived,
+// the products stay locked FOR SHARE until the offer is in.
+func checkOfferBundle(tx *gorm.DB, bundleID uint) error {
+	var productModels []model.Product
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE", Table: clause.Table{Name: "products"}}).
+		Select("products.id", "products.archived_at").
+		Joins("JOIN bundle_items ON bundle_items.product_id = products.id").
+		Where("bundle_items.bundle_id = ?", bundleID).
+		Find(&productModels).Error; err != nil {
+		return &apperror.OfferError{
 			Code:    apperror.DatabaseError,
-			Message: "offer to create product",
+			Message: "failed to check offer bundle",
 			Err:     err,
 		}
 	}
+	if len(productModels) == 0 {
+		return apperror.ErrOfferBundleNotFound
+	}
+
+	for _, p := range productModels {
+		if p.ArchivedAt != nil {
+			return apperror.ErrOfferProductArchived
+		}
+	}
 
-	return offer.ID, nil
+	return nil
 }
 
+// GetOfferByID returns the offer with its negotiation thread.
 func (r *offerRepository) GetOfferByID(offerID uint) (entity.Offer, error) {
-	var offer entity.Offer
-	if err := r.db.Where("id = ?", offerID).First(&offer).Error; err != nil {
+	var offerModel model.Offer
+	if err := r.db.Preload("Rounds", func(db *gorm.DB) *gorm.DB {
+		return db.Order("round")
+	}).Where("id = ?", offerID).First(&offerModel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entity.Offer{}, apperror.ErrOfferNotFound
 		}
@@ -53,7 +151,7 @@ func (r *offerRepository) GetOfferByID(offerID uint) (entity.Offer, error) {
 		}
 	}
 
-	return offer, nil
+	return model.ConvertOfferToEntity(offerModel), nil
 }
 
 func (r *offerRepository) SelectUserOffers(userID uint, limit, offset int) ([]entity.Offer, int64, error) {
@@ -66,8 +164,8 @@ func (r *offerRepository) SelectUserOffers(userID uint, limit, offset int) ([]en
 		}
 	}
 
-	var offers []entity.Offer
-	if err := r.db.Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&offers).Error; err != nil {
+	var offerModels []model.Offer
+	if err := r.db.Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&offerModels).Error; err != nil {
 		return nil, 0, &apperror.OfferError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to fetch user offers",
@@ -75,24 +173,42 @@ func (r *offerRepository) SelectUserOffers(userID uint, limit, offset int) ([]en
 		}
 	}
 
+	offers := make([]entity.Offer, 0, len(offerModels))
+	for _, o := range offerModels {
+		offers = append(offers, model.ConvertOfferToEntity(o))
+	}
+
 	return offers, total, nil
 }
 
-func (r *offerRepository) UpdateOfferStatus(offerID uint, status string) (entity.Offer, error) {
-	tx := r.db.Model(&model.Offer{}).Where("id = ?", offerID).Update("status", status)
-	if tx.Error != nil {
-		return entity.Offer{}, &apperror.OfferError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to update offer status",
-			Err:     tx.Error,
+// UpdateOfferStatus moves the offer from one status to another and settles
+// its stock reservations: a completed offer keeps the stock, any other status
+// returns it. An offer can't be completed without an active reservation.
+func (r *offerRepository) UpdateOfferStatus(offerID uint, from, to string) (entity.Offer, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := transitionOffer(tx, offerID, from, to); err != nil {
+			return err
 		}
-	}
-	if tx.RowsAffected == 0 {
-		return entity.Offer{}, apperror.ErrOfferNotFound
+
+		if to == offer.StatusCompleted {
+			fulfilled, err := releaseOfferReservation(tx, offerID, model.ReservationFulfilled)
+			if err != nil {
+				return err
+			}
+			if fulfilled == 0 {
+				return apperror.ErrOfferNotReserved
+			}
+			return nil
+		}
+		_, err := releaseOfferReservation(tx, offerID, model.ReservationReleased)
+		return err
+	})
+	if err != nil {
+		return entity.Offer{}, err
 	}
 
-	var offer entity.Offer
-	if err := r.db.Where("id = ?", offerID).First(&offer).Error; err != nil {
+	var offerModel model.Offer
+	if err := r.db.Where("id = ?", offerID).First(&offerModel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entity.Offer{}, apperror.ErrOfferNotFound
 		}
@@ -103,12 +219,48 @@ func (r *offerRepository) UpdateOfferStatus(offerID uint, status string) (entity
 		}
 	}
 
-	return offer, nil
+	return model.ConvertOfferToEntity(offerModel), nil
+}
+
+// transitionOffer changes the status of the offer with an UPDATE conditional
+// on its current status, so an offer changed concurrently fails with
+// ErrOfferTransition instead of being overwritten. The updated offer is
+// returned.
+func transitionOffer(tx *gorm.DB, offerID uint, from, to string) (model.Offer, error) {
+	var offerModel model.Offer
+	result := tx.Model(&offerModel).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND status = ?", offerID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return model.Offer{}, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update offer status",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected > 0 {
+		return offerModel, nil
+	}
+
+	var count int64
+	if err := tx.Model(&model.Offer{}).Where("id = ?", offerID).Count(&count).Error; err != nil {
+		return model.Offer{}, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to get offer",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		return model.Offer{}, apperror.ErrOfferNotFound
+	}
+
+	return model.Offer{}, apperror.ErrOfferTransition
 }
 
 func (r *offerRepository) DeleteOffer(offerID uint) (entity.Offer, error) {
-	var offer entity.Offer
-	if err := r.db.Where("id = ?", offerID).First(&offer).Error; err != nil {
+	var offerModel model.Offer
+	if err := r.db.Where("id = ?", offerID).First(&offerModel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entity.Offer{}, apperror.ErrOfferNotFound
 		}
@@ -119,13 +271,24 @@ func (r *offerRepository) DeleteOffer(offerID uint) (entity.Offer, error) {
 		}
 	}
 
-	if err := r.db.Delete(&offer).Error; err != nil {
-		return entity.Offer{}, &apperror.OfferError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to delete offer",
-			Err:     err,
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := releaseOfferReservation(tx, offerModel.ID, model.ReservationReleased); err != nil {
+			return err
 		}
+
+		if err := tx.Delete(&model.Offer{}, offerModel.ID).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete offer",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Offer{}, err
 	}
 
-	return offer, nil
+	return model.ConvertOfferToEntity(offerModel), nil
 }
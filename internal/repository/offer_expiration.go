@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+// ExpireOffers moves up to limit offers still under negotiation whose
+// expiration passed before now to expired, and notifies the buyer and the
+// store staff of each. Rows locked by another replica are skipped.
+func (r *offerRepository) ExpireOffers(now time.Time, limit int) (int, error) {
+	var expired []model.Offer
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`
+			UPDATE offers
+			SET status = ?, updated_at = ?
+			WHERE id IN (
+				SELECT id FROM offers
+				WHERE status IN ? AND expires_at < ?
+				ORDER BY expires_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, user_id, store_id`,
+			offer.StatusExpired, now,
+			offer.OpenStatuses, now,
+			limit,
+		).Scan(&expired).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to expire offers",
+				Err:     err,
+			}
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		storeIDs := make([]uint, 0, len(expired))
+		for _, o := range expired {
+			storeIDs = append(storeIDs, o.StoreID)
+		}
+		var members []model.StoreMember
+		if err := tx.Select("store_id", "user_id").
+			Where("store_id IN ?", storeIDs).
+			Find(&members).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch store members",
+				Err:     err,
+			}
+		}
+		memberIDs := make(map[uint][]uint)
+		for _, m := range members {
+			memberIDs[m.StoreID] = append(memberIDs[m.StoreID], m.UserID)
+		}
+
+		for _, o := range expired {
+			userIDs := append([]uint{o.UserID}, memberIDs[o.StoreID]...)
+			message := fmt.Sprintf("Offer %d has expired", o.ID)
+			if err := notifyOffer(tx, userIDs, o.ID, message); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(expired), nil
+}
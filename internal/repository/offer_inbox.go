@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+// storeOfferOrders maps the store inbox orders to ORDER BY clauses. Closed
+// offers don't expire anymore, the expiring order puts them last.
+var storeOfferOrders = map[string]string{
+	offer.SortNewest:    "created_at DESC, id DESC",
+	offer.SortPriceHigh: "price DESC, created_at DESC, id DESC",
+	offer.SortExpiring:  "status NOT IN ('" + strings.Join(offer.OpenStatuses, "', '") + "'), expires_at, id",
+}
+
+// SelectStoreOffers returns the offers made to the store that match the
+// filter, without their negotiation threads.
+func (r *offerRepository) SelectStoreOffers(
+	storeID uint,
+	filter offer.StoreOfferFilter,
+	sort string,
+	offset, limit int,
+) ([]entity.Offer, int64, error) {
+	var total int64
+	if err := r.db.Model(&model.Offer{}).
+		Where("store_id = ?", storeID).
+		Scopes(filterStoreOffers(filter)).
+		Count(&total).Error; err != nil {
+		return nil, 0, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count store offers",
+			Err:     err,
+		}
+	}
+
+	var offerModels []model.Offer
+	if err := r.db.Where("store_id = ?", storeID).
+		Scopes(filterStoreOffers(filter)).
+		Order(storeOfferOrders[sort]).
+		Offset(offset).
+		Limit(limit).
+		Find(&offerModels).Error; err != nil {
+		return nil, 0, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store offers",
+			Err:     err,
+		}
+	}
+
+	offers := make([]entity.Offer, 0, len(offerModels))
+	for _, o := range offerModels {
+		offers = append(offers, model.ConvertOfferToEntity(o))
+	}
+
+	return offers, total, nil
+}
+
+// CountStoreOffersByStatus returns how many offers were made to the store in
+// each status, statuses without offers are left out.
+func (r *offerRepository) CountStoreOffersByStatus(storeID uint) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.Model(&model.Offer{}).
+		Select("status, COUNT(*) AS count").
+		Where("store_id = ?", storeID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count store offers by status",
+			Err:     err,
+		}
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+func filterStoreOffers(filter offer.StoreOfferFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.ProductID != nil {
+			db = db.Where("product_id = ?", *filter.ProductID)
+		}
+		if filter.MinPrice != nil {
+			db = db.Where("price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			db = db.Where("price <= ?", *filter.MaxPrice)
+		}
+		if filter.CreatedAfter != nil {
+			db = db.Where("created_at >= ?", *filter.CreatedAfter)
+		}
+		if filter.CreatedBefore != nil {
+			db = db.Where("created_at < ?", *filter.CreatedBefore)
+		}
+		return db
+	}
+}
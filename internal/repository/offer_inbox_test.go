@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without a database and reports the SQL of the
+// last query.
+func dryRunDB(t *testing.T) (*gorm.DB, *string) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+
+	var query string
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		query = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+	}); err != nil {
+		t.Fatalf("failed to register the query callback: %v", err)
+	}
+
+	return db, &query
+}
+
+func TestSelectStoreOffersExpiringPutsClosedLast(t *testing.T) {
+	db, query := dryRunDB(t)
+
+	filter := offer.StoreOfferFilter{}
+	if _, _, err := NewOfferRepository(db).SelectStoreOffers(3, filter, offer.SortExpiring, 0, 20); err != nil {
+		t.Fatalf("SelectStoreOffers unexpected error: %v", err)
+	}
+
+	want := `ORDER BY status NOT IN ('pending', 'countered'), expires_at, id`
+	if !strings.Contains(*query, want) {
+		t.Errorf("SelectStoreOffers(expiring) query = %s, want it to contain %s", *query, want)
+	}
+}
+
+func TestSelectStoreOffersPriceBounds(t *testing.T) {
+	db, query := dryRunDB(t)
+
+	minPrice, maxPrice := money.New(1000, "USD"), money.New(250000, "USD")
+	filter := offer.StoreOfferFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}
+	if _, _, err := NewOfferRepository(db).SelectStoreOffers(3, filter, offer.SortNewest, 0, 20); err != nil {
+		t.Fatalf("SelectStoreOffers unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"price >= '10.00'", "price <= '2500.00'"} {
+		if !strings.Contains(*query, want) {
+			t.Errorf("SelectStoreOffers query = %s, want it to contain %s", *query, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+// CounterOffer moves the offer from one status to another, appends the round
+// to its thread and makes the round price the offered one, expiring offerTTL
+// from now. The other side is notified. The status UPDATE keeps the offer row
+// locked, so concurrent counters can't exceed the round limit.
+func (r *offerRepository) CounterOffer(
+	round offer.Round,
+	from, to string,
+	offerTTL time.Duration,
+) (entity.Offer, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		offerModel, err := transitionOffer(tx, round.OfferID, from, to)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.OfferRound{}).Where("offer_id = ?", round.OfferID).Count(&count).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count offer rounds",
+				Err:     err,
+			}
+		}
+		if int(count) >= offerModel.MaxRounds {
+			return apperror.ErrOfferRoundLimit
+		}
+
+		roundModel := model.ConvertOfferRoundFromSvc(round)
+		roundModel.Round = int(count) + 1
+		if err := tx.Create(&roundModel).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create offer round",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Model(&model.Offer{ID: round.OfferID}).Updates(map[string]interface{}{
+			"price":      round.Price,
+			"currency":   round.Price.Currency,
+			"expires_at": time.Now().Add(offerTTL),
+		}).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to update offer price",
+				Err:     err,
+			}
+		}
+
+		message := fmt.Sprintf("Offer %d countered with %s", round.OfferID, round.Price)
+		if round.Side == offer.SideStore {
+			return notifyOffer(tx, []uint{offerModel.UserID}, round.OfferID, message)
+		}
+
+		memberIDs, err := storeMemberIDs(tx, offerModel.StoreID)
+		if err != nil {
+			return err
+		}
+		return notifyOffer(tx, memberIDs, round.OfferID, message)
+	})
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	return r.GetOfferByID(round.OfferID)
+}
+
+func storeMemberIDs(tx *gorm.DB, storeID uint) ([]uint, error) {
+	var memberIDs []uint
+	if err := tx.Model(&model.StoreMember{}).
+		Where("store_id = ?", storeID).
+		Pluck("user_id", &memberIDs).Error; err != nil {
+		return nil, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store members",
+			Err:     err,
+		}
+	}
+
+	return memberIDs, nil
+}
+
+func notifyOffer(tx *gorm.DB, userIDs []uint, offerID uint, message string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]model.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, model.Notification{
+			UserID:  userID,
+			Type:    model.NotificationOffer,
+			OfferID: &offerID,
+			Message: message,
+		})
+	}
+
+	if err := tx.Create(&notifications).Error; err != nil {
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to create offer notifications",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+	"github.com/PosokhovVadim/stawberry/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+const lowestPriceWindow = 30 * 24 * time.Hour
+
+// lowestPrice30dColumn selects the lowest price of a product since the window
+// start: the current price, every price set within the window and the price
+// that was in effect when the window started.
+const lowestPrice30dColumn = `LEAST(
+	products.price,
+	(SELECT MIN(h.new_price) FROM product_price_history h
+		WHERE h.product_id = products.id AND h.changed_at >= @since),
+	(SELECT h.new_price FROM product_price_history h
+		WHERE h.product_id = products.id AND h.changed_at < @since
+		ORDER BY h.changed_at DESC LIMIT 1)
+) AS lowest_price_30d`
+
+func (r *productRepository) SelectPriceHistory(productID string, offset, limit int) ([]entity.PriceChange, int, error) {
+	var exists int64
+	if err := r.db.Model(&model.Product{}).
+		Scopes(publishedProducts).
+		Where("id = ?", productID).
+		Count(&exists).Error; err != nil {
+		return nil, 0, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product",
+			Err:     err,
+		}
+	}
+	if exists == 0 {
+		return nil, 0, apperror.ErrProductNotFound
+	}
+
+	var total int64
+	if err := r.db.Model(&model.ProductPriceHistory{}).
+		Where("product_id = ?", productID).
+		Count(&total).Error; err != nil {
+		return nil, 0, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count price history",
+			Err:     err,
+		}
+	}
+
+	var historyModels []model.ProductPriceHistory
+	if err := r.db.Where("product_id = ?", productID).
+		Order("changed_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&historyModels).Error; err != nil {
+		return nil, 0, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch price history",
+			Err:     err,
+		}
+	}
+
+	history := make([]entity.PriceChange, 0, len(historyModels))
+	for _, h := range historyModels {
+		history = append(history, model.ConvertPriceHistoryToEntity(h))
+	}
+
+	return history, int(total), nil
+}
+
+func insertPriceChange(tx *gorm.DB, productID uint, oldPrice *money.Money, newPrice money.Money, actorID *uint) error {
+	change := model.ProductPriceHistory{
+		ProductID: productID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		ChangedBy: actorID,
+		ChangedAt: time.Now(),
+	}
+	if err := tx.Create(&change).Error; err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to record price change",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
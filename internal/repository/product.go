@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
 	"github.com/PosokhovVadim/stawberry/internal/repository/model"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -21,29 +24,61 @@ func NewProductRepository(db *gorm.DB) *productRepository {
 	return &productRepository{db: db}
 }
 
-func (r *productRepository) InsertProduct(product product.Product) (uint, error) {
+func (r *productRepository) InsertProduct(product product.Product, actorID *uint) (uint, error) {
 	productModel := model.ConvertProductFromSvc(product)
-	if err := r.db.Create(productModel).Error; err != nil {
-		if isDuplicateError(err) {
-			return 0, &apperror.ProductError{
-				Code:    apperror.DuplicateError,
-				Message: "product with this id already exists",
-				Err:     err,
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Products are priced in the store currency unless told otherwise
+		if productModel.Currency == "" {
+			currency, err := storeCurrency(tx, productModel.StoreID)
+			if err != nil {
+				return err
 			}
+			productModel.Currency = currency
+			productModel.Price = productModel.Price.WithCurrency(currency)
 		}
-		return 0, &apperror.ProductError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to create product",
-			Err:     err,
+
+		var err error
+		if productModel.Slug, err = uniqueProductSlug(tx, 0, productModel.Name); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&productModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.ProductError{
+					Code:    apperror.DuplicateError,
+					Message: "product with this id already exists",
+					Err:     err,
+				}
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create product",
+				Err:     err,
+			}
 		}
+
+		return insertPriceChange(tx, productModel.ID, nil, productModel.Price, actorID)
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	return productModel.ID, nil
 }
 
 func (r *productRepository) GetProductByID(id string) (entity.Product, error) {
+	return r.getProduct("products.id = ?", id)
+}
+
+// getProduct fetches the product matched by the condition with its variants,
+// images, attributes and computed columns.
+func (r *productRepository) getProduct(query string, args ...interface{}) (entity.Product, error) {
 	var productModel model.Product
-	if err := r.db.Where("id = ?", id).First(&productModel).Error; err != nil {
+	if err := r.db.Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Scopes(preloadImages, preloadAttributes, withProductStats).
+		Where(query, args...).
+		First(&productModel).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entity.Product{}, apperror.ErrProductNotFound
 		}
@@ -57,9 +92,12 @@ func (r *productRepository) GetProductByID(id string) (entity.Product, error) {
 	return model.ConvertProductToEntity(productModel), nil
 }
 
-func (r *productRepository) SelectProducts(offset, limit int) ([]entity.Product, int, error) {
+func (r *productRepository) SelectProducts(
+	filter product.ProductFilter,
+	offset, limit int,
+) ([]entity.Product, int, error) {
 	var total int64
-	if err := r.db.Model(&model.Product{}).Count(&total).Error; err != nil {
+	if err := r.db.Model(&model.Product{}).Scopes(activeProducts, filterProducts(filter)).Count(&total).Error; err != nil {
 		return nil, 0, &apperror.ProductError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to count products",
@@ -67,8 +105,11 @@ func (r *productRepository) SelectProducts(offset, limit int) ([]entity.Product,
 		}
 	}
 
-	var products []entity.Product
-	if err := r.db.Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	var productModels []model.Product
+	if err := r.db.Scopes(activeProducts, filterProducts(filter), preloadImages, preloadAttributes, withProductStats).
+		Offset(offset).
+		Limit(limit).
+		Find(&productModels).Error; err != nil {
 		return nil, 0, &apperror.ProductError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to fetch products",
@@ -76,12 +117,19 @@ func (r *productRepository) SelectProducts(offset, limit int) ([]entity.Product,
 		}
 	}
 
-	return products, int(total), nil
+	return convertProductsToEntity(productModels), int(total), nil
 }
 
-func (r *productRepository) SelectStoreProducts(id string, offset, limit int) ([]entity.Product, int, error) {
+func (r *productRepository) SelectStoreProducts(
+	id string,
+	filter product.ProductFilter,
+	offset, limit int,
+) ([]entity.Product, int, error) {
 	var total int64
-	if err := r.db.Model(&model.Product{}).Where("store_id = ?", id).Count(&total).Error; err != nil {
+	if err := r.db.Model(&model.Product{}).
+		Scopes(activeProducts, filterProducts(filter)).
+		Where("store_id = ?", id).
+		Count(&total).Error; err != nil {
 		return nil, 0, &apperror.ProductError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to count store products",
@@ -89,8 +137,12 @@ func (r *productRepository) SelectStoreProducts(id string, offset, limit int) ([
 		}
 	}
 
-	var products []entity.Product
-	if err := r.db.Where("store_id = ?", id).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	var productModels []model.Product
+	if err := r.db.Scopes(activeProducts, filterProducts(filter), preloadImages, preloadAttributes, withProductStats).
+		Where("store_id = ?", id).
+		Offset(offset).
+		Limit(limit).
+		Find(&productModels).Error; err != nil {
 		return nil, 0, &apperror.ProductError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to fetch store products",
@@ -98,35 +150,188 @@ func (r *productRepository) SelectStoreProducts(id string, offset, limit int) ([
 		}
 	}
 
-	return products, int(total), nil
+	return convertProductsToEntity(productModels), int(total), nil
 }
 
-func (r *productRepository) UpdateProduct(id string, update product.UpdateProduct) error {
+// UpdateProduct applies the update and bumps the product version. When
+// ifMatch is not empty the update only happens while the version is one of
+// them, the check is part of the UPDATE so concurrent edits can't both pass.
+// A price change is recorded in the price history and a rename moves the
+// product to a new slug within the same transaction.
+func (r *productRepository) UpdateProduct(
+	id string,
+	update product.UpdateProduct,
+	ifMatch []int,
+	actorID *uint,
+) (int, error) {
 	updateModel := model.ConvertUpdateProductFromSvc(update)
-	tx := r.db.Model(&model.Product{}).Where("id = ?", id).Updates(updateModel)
-	if tx.Error != nil {
-		if isDuplicateError(tx.Error) {
+	var version int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "slug", "name", "price", "currency", "version").
+			Where("id = ?", id).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrProductNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to lock product",
+				Err:     err,
+			}
+		}
+
+		// Prices are always given in the product currency
+		current.Price = current.Price.WithCurrency(current.Currency)
+		if updateModel.Price != nil {
+			price := updateModel.Price.WithCurrency(current.Currency)
+			updateModel.Price = &price
+		}
+
+		columns := updateModel.Columns()
+		columns["version"] = gorm.Expr("version + 1")
+		if updateModel.Name != nil && *updateModel.Name != current.Name {
+			newSlug, err := renameProductSlug(tx, current.ID, current.Slug, *updateModel.Name)
+			if err != nil {
+				return err
+			}
+			columns["slug"] = newSlug
+		}
+
+		query := tx.Model(&model.Product{}).Where("id = ?", id)
+		if len(ifMatch) > 0 {
+			query = query.Where("version IN ?", ifMatch)
+		}
+		result := query.Updates(columns)
+		if result.Error != nil {
+			if isDuplicateError(result.Error) {
+				return &apperror.ProductError{
+					Code:    apperror.DuplicateError,
+					Message: "product with these details already exists",
+					Err:     result.Error,
+				}
+			}
 			return &apperror.ProductError{
-				Code:    apperror.DuplicateError,
-				Message: "product with these details already exists",
-				Err:     tx.Error,
+				Code:    apperror.DatabaseError,
+				Message: "failed to update product",
+				Err:     result.Error,
 			}
 		}
+
+		if result.RowsAffected == 0 {
+			return apperror.ErrProductVersionMismatch
+		}
+		version = current.Version + 1
+
+		if updateModel.Price == nil || updateModel.Price.Cmp(current.Price) == 0 {
+			return nil
+		}
+		if err := insertPriceChange(tx, current.ID, &current.Price, *updateModel.Price, actorID); err != nil {
+			return err
+		}
+
+		if updateModel.Price.Cmp(current.Price) > 0 {
+			return nil
+		}
+		name := current.Name
+		if updateModel.Name != nil {
+			name = *updateModel.Name
+		}
+		return firePriceAlerts(tx, current.ID, name, *updateModel.Price)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// bumpProductVersion changes the product ETag after a write to one of the rows
+// it is rendered with, such as its variants, images or attributes. productID
+// may also be a subquery selecting the id.
+func bumpProductVersion(db *gorm.DB, productID interface{}) error {
+	if err := db.Model(&model.Product{}).
+		Where("id = (?)", productID).
+		Update("version", gorm.Expr("version + 1")).Error; err != nil {
 		return &apperror.ProductError{
 			Code:    apperror.DatabaseError,
-			Message: "failed to update product",
-			Err:     tx.Error,
+			Message: "failed to update product version",
+			Err:     err,
 		}
 	}
 
-	if tx.RowsAffected == 0 {
-		return apperror.ErrProductNotFound
+	return nil
+}
+
+func convertProductsToEntity(productModels []model.Product) []entity.Product {
+	products := make([]entity.Product, 0, len(productModels))
+	for _, p := range productModels {
+		products = append(products, model.ConvertProductToEntity(p))
 	}
+	return products
+}
 
-	return nil
+func storeCurrency(tx *gorm.DB, storeID uint) (string, error) {
+	currency, err := NewStoreRepository(tx).GetStoreCurrency(storeID)
+	if err != nil {
+		return "", &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store currency",
+			Err:     err,
+		}
+	}
+	if currency == "" {
+		return "", apperror.ErrStoreNotFound
+	}
+
+	return currency, nil
+}
+
+// productBasePrice is the product price in money.DefaultCurrency, price
+// filters compare it so they hold across stores selling in other currencies.
+var productBasePrice = inBaseCurrency("products.price", "products.currency")
+
+// filterProducts applies the listing filters shared by product listings and
+// catalog exports.
+func filterProducts(filter product.ProductFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Category != "" {
+			db = db.Where("products.category = ?", filter.Category)
+		}
+		if filter.MinPrice != nil {
+			db = db.Where(productBasePrice+" >= "+inBaseCurrency("@price", "@currency"),
+				sql.Named("price", *filter.MinPrice), sql.Named("currency", filter.MinPrice.Currency))
+		}
+		if filter.MaxPrice != nil {
+			db = db.Where(productBasePrice+" <= "+inBaseCurrency("@price", "@currency"),
+				sql.Named("price", *filter.MaxPrice), sql.Named("currency", filter.MaxPrice.Currency))
+		}
+		if filter.InStock != nil {
+			db = db.Where("products.in_stock = ?", *filter.InStock)
+		}
+		if filter.Status != "" {
+			db = db.Where("products.status = ?", filter.Status)
+		}
+		return filterAttributes(db, filter.Attributes)
+	}
 }
 
 func isDuplicateError(err error) bool {
 	return strings.Contains(err.Error(), "duplicate") ||
 		strings.Contains(err.Error(), "unique violation")
 }
+
+func isForeignKeyError(err error) bool {
+	return strings.Contains(err.Error(), "foreign key") ||
+		strings.Contains(err.Error(), "SQLSTATE 23503")
+}
+
+// withProductStats selects the computed product columns: the lowest price of
+// the last 30 days, the number of buyers watching the product and its rating.
+func withProductStats(db *gorm.DB) *gorm.DB {
+	return db.Select(
+		"products.*, "+lowestPrice30dColumn+", "+watcherCountColumn+", "+reviewStatsColumns,
+		sql.Named("since", time.Now().Add(-lowestPriceWindow)),
+	)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+// ArchiveProduct hides the product from listings and rejects its offers still
+// under negotiation, including those on bundles holding it. Archiving an
+// archived product is a no-op.
+func (r *productRepository) ArchiveProduct(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Product{}).
+			Where("id = ? AND archived_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"archived_at": time.Now(),
+				"version":     gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to archive product",
+				Err:     result.Error,
+			}
+		}
+		if result.RowsAffected == 0 {
+			return productExists(tx, id)
+		}
+
+		if err := tx.Model(&model.Offer{}).
+			Where("product_id = ? OR bundle_id IN (?)", id,
+				tx.Model(&model.BundleItem{}).Select("bundle_id").Where("product_id = ?", id)).
+			Where("status IN ?", offer.OpenStatuses).
+			Update("status", offer.StatusRejected).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to reject pending offers",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *productRepository) RestoreProduct(id string) error {
+	result := r.db.Model(&model.Product{}).
+		Where("id = ? AND archived_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"archived_at": nil,
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to restore product",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return productExists(r.db, id)
+	}
+
+	return nil
+}
+
+// productExists returns ErrProductNotFound if there is no product with the id.
+func productExists(db *gorm.DB, id string) error {
+	var count int64
+	if err := db.Model(&model.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		return apperror.ErrProductNotFound
+	}
+
+	return nil
+}
+
+func activeProducts(db *gorm.DB) *gorm.DB {
+	return db.Where("products.archived_at IS NULL")
+}
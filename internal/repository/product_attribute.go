@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *productRepository) InsertAttributeDefinition(definition product.AttributeDefinition) (uint, error) {
+	attributeModel := model.ConvertAttributeDefinitionFromSvc(definition)
+	if err := r.db.Create(&attributeModel).Error; err != nil {
+		if isDuplicateError(err) {
+			return 0, &apperror.ProductError{
+				Code:    apperror.DuplicateError,
+				Message: "attribute with this key already exists in the category",
+				Err:     err,
+			}
+		}
+		return 0, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to create attribute",
+			Err:     err,
+		}
+	}
+
+	return attributeModel.ID, nil
+}
+
+func (r *productRepository) SelectAttributeDefinitions(category string) ([]entity.AttributeDefinition, error) {
+	var attributeModels []model.CategoryAttribute
+	if err := r.db.Where("category = ?", category).Order("position, id").Find(&attributeModels).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch attributes",
+			Err:     err,
+		}
+	}
+
+	definitions := make([]entity.AttributeDefinition, 0, len(attributeModels))
+	for _, a := range attributeModels {
+		definitions = append(definitions, model.ConvertAttributeDefinitionToEntity(a))
+	}
+
+	return definitions, nil
+}
+
+// DeleteAttributeDefinition removes the attribute and, through the foreign
+// key, its values on every product.
+func (r *productRepository) DeleteAttributeDefinition(category string, attributeID uint) error {
+	tx := r.db.Where("id = ? AND category = ?", attributeID, category).Delete(&model.CategoryAttribute{})
+	if tx.Error != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to delete attribute",
+			Err:     tx.Error,
+		}
+	}
+
+	if tx.RowsAffected == 0 {
+		return apperror.ErrAttributeNotFound
+	}
+
+	return nil
+}
+
+// ReplaceProductAttributes sets the attribute values of the product, values
+// of attributes that aren't given are removed.
+func (r *productRepository) ReplaceProductAttributes(productID uint, values []product.AttributeValue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&model.ProductAttributeValue{}).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to clear product attributes",
+				Err:     err,
+			}
+		}
+
+		if err := bumpProductVersion(tx, productID); err != nil {
+			return err
+		}
+
+		if len(values) == 0 {
+			return nil
+		}
+
+		valueModels := make([]model.ProductAttributeValue, 0, len(values))
+		for _, v := range values {
+			v.ProductID = productID
+			valueModels = append(valueModels, model.ConvertAttributeValueFromSvc(v))
+		}
+		if err := tx.Omit("Attribute").Create(&valueModels).Error; err != nil {
+			if isForeignKeyError(err) {
+				return apperror.ErrProductNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to save product attributes",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+}
+
+// SelectProductFacets counts the active products matching the filter per
+// attribute value in a single GROUP BY. An empty storeID counts all stores.
+func (r *productRepository) SelectProductFacets(storeID string, filter product.ProductFilter) ([]entity.Facet, error) {
+	products := r.db.Model(&model.Product{}).
+		Select("products.id").
+		Scopes(activeProducts, filterProducts(filter))
+	if storeID != "" {
+		products = products.Where("products.store_id = ?", storeID)
+	}
+
+	var rows []struct {
+		Key   string
+		Name  string
+		Value string
+		Count int
+	}
+	if err := r.db.Table("product_attribute_values AS v").
+		Select("a.key, a.name, v.value_text AS value, COUNT(*) AS count").
+		Joins("JOIN category_attributes a ON a.id = v.attribute_id").
+		Where("v.product_id IN (?)", products).
+		Group("a.key, a.name, a.position, v.value_text").
+		Order("a.position, a.key, count DESC, v.value_text").
+		Scan(&rows).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count product facets",
+			Err:     err,
+		}
+	}
+
+	// Attributes with the same key in several categories are counted together
+	var facets []entity.Facet
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.Key]
+		if !ok {
+			i = len(facets)
+			index[row.Key] = i
+			facets = append(facets, entity.Facet{Attribute: row.Key, Name: row.Name})
+		}
+		facets[i].Values = append(facets[i].Values, entity.FacetValue{Value: row.Value, Count: row.Count})
+	}
+
+	return facets, nil
+}
+
+func preloadAttributes(db *gorm.DB) *gorm.DB {
+	return db.Preload("AttributeValues.Attribute")
+}
+
+// filterAttributes keeps the products that match every attribute filter.
+func filterAttributes(db *gorm.DB, filters []product.AttributeFilter) *gorm.DB {
+	for _, filter := range filters {
+		conditions := []string{"a.key = ?"}
+		args := []interface{}{filter.Key}
+
+		if len(filter.Values) > 0 {
+			values := make([]string, 0, len(filter.Values))
+			for _, v := range filter.Values {
+				values = append(values, strings.ToLower(v))
+			}
+			conditions = append(conditions, "lower(v.value_text) IN ?")
+			args = append(args, values)
+		}
+		if filter.Min != nil {
+			conditions = append(conditions, "v.value_number >= ?")
+			args = append(args, *filter.Min)
+		}
+		if filter.Max != nil {
+			conditions = append(conditions, "v.value_number <= ?")
+			args = append(args, *filter.Max)
+		}
+
+		db = db.Where(`EXISTS (
+			SELECT 1 FROM product_attribute_values v
+			JOIN category_attributes a ON a.id = v.attribute_id
+			WHERE v.product_id = products.id AND `+strings.Join(conditions, " AND ")+`)`, args...)
+	}
+
+	return db
+}
@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertProductImage appends the image to the end of the product gallery.
+// The first image of a product becomes its primary one.
+func (r *productRepository) InsertProductImage(image product.ProductImage) (entity.ProductImage, error) {
+	imageModel := model.ConvertProductImageFromSvc(image)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the product so concurrent uploads get distinct positions
+		var productModel model.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", image.ProductID).
+			First(&productModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrProductNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to lock product",
+				Err:     err,
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&model.ProductImage{}).Where("product_id = ?", image.ProductID).Count(&count).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count product images",
+				Err:     err,
+			}
+		}
+
+		imageModel.Position = int(count)
+		imageModel.IsPrimary = count == 0
+		if err := tx.Create(&imageModel).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create product image",
+				Err:     err,
+			}
+		}
+
+		return bumpProductVersion(tx, image.ProductID)
+	})
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	return model.ConvertProductImageToEntity(imageModel), nil
+}
+
+// ReorderProductImages sets image positions to their index in imageIDs, which
+// must list every image of the product exactly once.
+func (r *productRepository) ReorderProductImages(productID uint, imageIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var images []model.ProductImage
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", productID).
+			Find(&images).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product images",
+				Err:     err,
+			}
+		}
+
+		if !sameImageSet(images, imageIDs) {
+			return apperror.ErrProductImageOrder
+		}
+
+		for position, imageID := range imageIDs {
+			if err := tx.Model(&model.ProductImage{}).
+				Where("id = ?", imageID).
+				Update("position", position).Error; err != nil {
+				return &apperror.ProductError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to reorder product images",
+					Err:     err,
+				}
+			}
+		}
+
+		return bumpProductVersion(tx, productID)
+	})
+}
+
+func (r *productRepository) SetPrimaryProductImage(productID, imageID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var image model.ProductImage
+		if err := tx.Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrProductImageNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product image",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Model(&model.ProductImage{}).
+			Where("product_id = ? AND is_primary", productID).
+			Update("is_primary", false).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to reset primary product image",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Model(&image).Update("is_primary", true).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to set primary product image",
+				Err:     err,
+			}
+		}
+
+		return bumpProductVersion(tx, productID)
+	})
+}
+
+// DeleteProductImage removes the image and, if it was the primary one,
+// promotes the next image in the gallery.
+func (r *productRepository) DeleteProductImage(productID, imageID uint) (entity.ProductImage, error) {
+	var image model.ProductImage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Renditions are removed by the cascade, they are loaded first so
+		// the caller can clean up their objects
+		var renditions []model.ProductImageRendition
+		if err := tx.Where("image_id = ?", imageID).Find(&renditions).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product image renditions",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Clauses(clause.Returning{}).
+			Where("id = ? AND product_id = ?", imageID, productID).
+			Delete(&image).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete product image",
+				Err:     err,
+			}
+		}
+		if image.ID == 0 {
+			return apperror.ErrProductImageNotFound
+		}
+		image.Renditions = renditions
+
+		if err := bumpProductVersion(tx, productID); err != nil {
+			return err
+		}
+
+		if !image.IsPrimary {
+			return nil
+		}
+
+		var next model.ProductImage
+		err := tx.Where("product_id = ?", productID).Order("position").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err == nil {
+			err = tx.Model(&next).Update("is_primary", true).Error
+		}
+		if err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to promote primary product image",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	return model.ConvertProductImageToEntity(image), nil
+}
+
+// ClaimPendingProductImages marks up to limit pending images as processing and
+// returns them. Images left in processing for longer than staleAfter, e.g. by a
+// crashed replica, are claimed again. Rows locked by another replica are skipped.
+func (r *productRepository) ClaimPendingProductImages(
+	limit int,
+	staleAfter time.Duration,
+) ([]entity.ProductImage, error) {
+	now := time.Now()
+
+	// The image status is part of the product, so its version is bumped too
+	var imageModels []model.ProductImage
+	if err := r.db.Raw(`
+		WITH claimed AS (
+			UPDATE product_images
+			SET processing_status = ?, claimed_at = ?, attempts = attempts + 1
+			WHERE id IN (
+				SELECT id FROM product_images
+				WHERE processing_status = ? OR (processing_status = ? AND claimed_at < ?)
+				ORDER BY id
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		), bumped AS (
+			UPDATE products SET version = version + 1, updated_at = ?
+			WHERE id IN (SELECT product_id FROM claimed)
+		)
+		SELECT * FROM claimed`,
+		product.ImageProcessing, now,
+		product.ImagePending, product.ImageProcessing, now.Add(-staleAfter),
+		limit, now,
+	).Scan(&imageModels).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to claim pending product images",
+			Err:     err,
+		}
+	}
+
+	images := make([]entity.ProductImage, 0, len(imageModels))
+	for _, i := range imageModels {
+		images = append(images, model.ConvertProductImageToEntity(i))
+	}
+
+	return images, nil
+}
+
+// CompleteProductImage replaces the renditions of the image and marks it ready.
+func (r *productRepository) CompleteProductImage(imageID uint, renditions []product.ProductImageRendition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("image_id = ?", imageID).Delete(&model.ProductImageRendition{}).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete product image renditions",
+				Err:     err,
+			}
+		}
+
+		renditionModels := make([]model.ProductImageRendition, 0, len(renditions))
+		for _, rendition := range renditions {
+			renditionModels = append(renditionModels, model.ConvertProductImageRenditionFromSvc(rendition))
+		}
+		if err := tx.Create(&renditionModels).Error; err != nil {
+			if isForeignKeyError(err) {
+				return apperror.ErrProductImageNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create product image renditions",
+				Err:     err,
+			}
+		}
+
+		return setProductImageStatus(tx, imageID, product.ImageReady)
+	})
+}
+
+func (r *productRepository) SetProductImageStatus(imageID uint, status string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return setProductImageStatus(tx, imageID, status)
+	})
+}
+
+func setProductImageStatus(db *gorm.DB, imageID uint, status string) error {
+	tx := db.Model(&model.ProductImage{}).Where("id = ?", imageID).Updates(map[string]interface{}{
+		"processing_status": status,
+		"claimed_at":        nil,
+	})
+	if tx.Error != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update product image status",
+			Err:     tx.Error,
+		}
+	}
+
+	if tx.RowsAffected == 0 {
+		return apperror.ErrProductImageNotFound
+	}
+
+	return bumpProductVersion(db, db.Model(&model.ProductImage{}).Select("product_id").Where("id = ?", imageID))
+}
+
+func preloadImages(db *gorm.DB) *gorm.DB {
+	return db.Preload("Images", orderImages).Preload("Images.Renditions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+}
+
+func orderImages(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+func sameImageSet(images []model.ProductImage, imageIDs []uint) bool {
+	if len(images) != len(imageIDs) {
+		return false
+	}
+
+	known := make(map[uint]bool, len(images))
+	for _, image := range images {
+		known[image.ID] = true
+	}
+	for _, id := range imageIDs {
+		if !known[id] {
+			return false
+		}
+		delete(known, id)
+	}
+
+	return true
+}
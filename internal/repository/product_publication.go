@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+
+	"gorm.io/gorm"
+)
+
+// PublishScheduledProducts publishes up to limit scheduled products due at
+// now. Rows locked by another replica are skipped.
+func (r *productRepository) PublishScheduledProducts(now time.Time, limit int) (int, error) {
+	result := r.db.Exec(`
+		UPDATE products
+		SET status = ?, version = version + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM products
+			WHERE status = ? AND publish_at <= ?
+			ORDER BY publish_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		product.StatusPublished, now,
+		product.StatusScheduled, now,
+		limit,
+	)
+	if result.Error != nil {
+		return 0, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to publish scheduled products",
+			Err:     result.Error,
+		}
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+func publishedProducts(db *gorm.DB) *gorm.DB {
+	return db.Where("products.status = ?", product.StatusPublished)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *productRepository) InsertProductVariant(variant product.ProductVariant) (uint, error) {
+	variantModel := model.ConvertProductVariantFromSvc(variant)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&variantModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.ProductError{
+					Code:    apperror.DuplicateError,
+					Message: "variant with this sku already exists",
+					Err:     err,
+				}
+			}
+			if isForeignKeyError(err) {
+				return apperror.ErrProductNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create product variant",
+				Err:     err,
+			}
+		}
+
+		return bumpProductVersion(tx, variant.ProductID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return variantModel.ID, nil
+}
+
+func (r *productRepository) UpdateProductVariant(
+	productID, variantID uint,
+	update product.UpdateProductVariant,
+) error {
+	updateModel := model.ConvertUpdateProductVariantFromSvc(update)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.ProductVariant{}).
+			Where("id = ? AND product_id = ?", variantID, productID).
+			Updates(updateModel)
+		if result.Error != nil {
+			if isDuplicateError(result.Error) {
+				return &apperror.ProductError{
+					Code:    apperror.DuplicateError,
+					Message: "variant with this sku already exists",
+					Err:     result.Error,
+				}
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to update product variant",
+				Err:     result.Error,
+			}
+		}
+
+		if result.RowsAffected == 0 {
+			return apperror.ErrProductVariantNotFound
+		}
+
+		return bumpProductVersion(tx, productID)
+	})
+}
+
+// DeleteProductVariant deletes a variant no offer or bundle was made on, the
+// foreign keys keep the others.
+func (r *productRepository) DeleteProductVariant(productID, variantID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND product_id = ?", variantID, productID).Delete(&model.ProductVariant{})
+		if result.Error != nil {
+			if isForeignKeyError(result.Error) {
+				return apperror.ErrProductVariantInUse
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete product variant",
+				Err:     result.Error,
+			}
+		}
+
+		if result.RowsAffected == 0 {
+			return apperror.ErrProductVariantNotFound
+		}
+
+		return bumpProductVersion(tx, productID)
+	})
+}
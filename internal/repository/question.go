@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/question"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type questionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestionRepository(db *gorm.DB) *questionRepository {
+	return &questionRepository{db: db}
+}
+
+// InsertQuestion stores the question and notifies every member of the store
+// selling the product.
+func (r *questionRepository) InsertQuestion(q question.Question) (entity.ProductQuestion, error) {
+	questionModel := model.ConvertQuestionFromSvc(q)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var productModel model.Product
+		if err := tx.Scopes(activeProducts, publishedProducts).
+			Select("id", "store_id", "name").
+			Where("id = ?", q.ProductID).
+			First(&productModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrQuestionProductNotFound
+			}
+			return &apperror.QuestionError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch question product",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Create(&questionModel).Error; err != nil {
+			return &apperror.QuestionError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create question",
+				Err:     err,
+			}
+		}
+		questionModel.StoreID = productModel.StoreID
+
+		var memberIDs []uint
+		if err := tx.Model(&model.StoreMember{}).
+			Where("store_id = ? AND user_id <> ?", productModel.StoreID, q.UserID).
+			Pluck("user_id", &memberIDs).Error; err != nil {
+			return &apperror.QuestionError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch store members",
+				Err:     err,
+			}
+		}
+
+		message := fmt.Sprintf("New question about %s", productModel.Name)
+		return notifyQuestion(tx, memberIDs, questionModel, model.NotificationQuestion, message)
+	})
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	return model.ConvertQuestionToEntity(questionModel), nil
+}
+
+func (r *questionRepository) GetQuestion(questionID uint) (entity.ProductQuestion, error) {
+	questionModel, err := selectQuestion(r.db, questionID)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	return model.ConvertQuestionToEntity(questionModel), nil
+}
+
+// AnswerQuestion sets the answer and notifies the asker the first time the
+// question is answered.
+func (r *questionRepository) AnswerQuestion(
+	questionID, userID uint,
+	answer string,
+) (entity.ProductQuestion, error) {
+	var questionModel model.ProductQuestion
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if questionModel, err = selectQuestion(tx.Clauses(clause.Locking{
+			Strength: "UPDATE",
+			Table:    clause.Table{Name: "product_questions"},
+		}), questionID); err != nil {
+			return err
+		}
+		firstAnswer := questionModel.Answer == nil
+
+		now := time.Now()
+		if err := tx.Model(&model.ProductQuestion{ID: questionID}).Updates(map[string]interface{}{
+			"answer":      answer,
+			"answered_by": userID,
+			"answered_at": now,
+		}).Error; err != nil {
+			return &apperror.QuestionError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to answer question",
+				Err:     err,
+			}
+		}
+		questionModel.Answer = &answer
+		questionModel.AnsweredBy = &userID
+		questionModel.AnsweredAt = &now
+		questionModel.UpdatedAt = now
+
+		if !firstAnswer {
+			return nil
+		}
+		return notifyQuestion(
+			tx,
+			[]uint{questionModel.UserID},
+			questionModel,
+			model.NotificationAnswer,
+			"Your question has been answered",
+		)
+	})
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	return model.ConvertQuestionToEntity(questionModel), nil
+}
+
+func (r *questionRepository) UpdateQuestion(
+	questionID uint,
+	moderation question.Moderation,
+) (entity.ProductQuestion, error) {
+	var questionModel model.ProductQuestion
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if questionModel, err = selectQuestion(tx.Clauses(clause.Locking{
+			Strength: "UPDATE",
+			Table:    clause.Table{Name: "product_questions"},
+		}), questionID); err != nil {
+			return err
+		}
+
+		updates := make(map[string]interface{})
+		if moderation.Question != nil {
+			updates["question"] = *moderation.Question
+		}
+		if moderation.Answer != nil {
+			if questionModel.Answer == nil {
+				return apperror.ErrQuestionNotAnswered
+			}
+			updates["answer"] = *moderation.Answer
+		}
+		if moderation.Hidden != nil {
+			switch {
+			case *moderation.Hidden && questionModel.HiddenAt == nil:
+				updates["hidden_at"] = time.Now()
+			case !*moderation.Hidden:
+				updates["hidden_at"] = nil
+			}
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.ProductQuestion{ID: questionID}).Updates(updates).Error; err != nil {
+			return &apperror.QuestionError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to moderate question",
+				Err:     err,
+			}
+		}
+
+		questionModel, err = selectQuestion(tx, questionID)
+		return err
+	})
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+
+	return model.ConvertQuestionToEntity(questionModel), nil
+}
+
+func (r *questionRepository) SelectProductQuestions(
+	productID uint,
+	offset, limit int,
+) ([]entity.ProductQuestion, int, error) {
+	return r.selectQuestions(func(db *gorm.DB) *gorm.DB {
+		return db.Where("product_questions.product_id = ? AND product_questions.answered_at IS NOT NULL", productID)
+	}, "product_questions.answered_at DESC, product_questions.id DESC", offset, limit)
+}
+
+func (r *questionRepository) SelectStoreQuestions(
+	storeID uint,
+	answered *bool,
+	offset, limit int,
+) ([]entity.ProductQuestion, int, error) {
+	return r.selectQuestions(func(db *gorm.DB) *gorm.DB {
+		db = db.Where("products.store_id = ?", storeID)
+		if answered != nil && *answered {
+			db = db.Where("product_questions.answered_at IS NOT NULL")
+		} else if answered != nil {
+			db = db.Where("product_questions.answered_at IS NULL")
+		}
+		return db
+	}, "product_questions.created_at DESC, product_questions.id DESC", offset, limit)
+}
+
+// selectQuestions pages through the visible questions matched by scope.
+func (r *questionRepository) selectQuestions(
+	scope func(db *gorm.DB) *gorm.DB,
+	order string,
+	offset, limit int,
+) ([]entity.ProductQuestion, int, error) {
+	var total int64
+	if err := r.db.Model(&model.ProductQuestion{}).
+		Scopes(joinQuestionProducts, visibleQuestions, scope).
+		Count(&total).Error; err != nil {
+		return nil, 0, &apperror.QuestionError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count questions",
+			Err:     err,
+		}
+	}
+
+	var questionModels []model.ProductQuestion
+	if err := r.db.Scopes(withQuestionStore, visibleQuestions, scope).
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(&questionModels).Error; err != nil {
+		return nil, 0, &apperror.QuestionError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch questions",
+			Err:     err,
+		}
+	}
+
+	questions := make([]entity.ProductQuestion, 0, len(questionModels))
+	for _, q := range questionModels {
+		questions = append(questions, model.ConvertQuestionToEntity(q))
+	}
+
+	return questions, int(total), nil
+}
+
+func selectQuestion(db *gorm.DB, questionID uint) (model.ProductQuestion, error) {
+	var questionModel model.ProductQuestion
+	if err := db.Scopes(withQuestionStore).
+		Where("product_questions.id = ?", questionID).
+		First(&questionModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ProductQuestion{}, apperror.ErrQuestionNotFound
+		}
+		return model.ProductQuestion{}, &apperror.QuestionError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch question",
+			Err:     err,
+		}
+	}
+
+	return questionModel, nil
+}
+
+func notifyQuestion(
+	tx *gorm.DB,
+	userIDs []uint,
+	questionModel model.ProductQuestion,
+	notificationType, message string,
+) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]model.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, model.Notification{
+			UserID:     userID,
+			Type:       notificationType,
+			ProductID:  &questionModel.ProductID,
+			QuestionID: &questionModel.ID,
+			Message:    message,
+		})
+	}
+
+	if err := tx.Create(&notifications).Error; err != nil {
+		return &apperror.QuestionError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to create question notifications",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func joinQuestionProducts(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN products ON products.id = product_questions.product_id")
+}
+
+// withQuestionStore selects the store selling the product of each question.
+func withQuestionStore(db *gorm.DB) *gorm.DB {
+	return joinQuestionProducts(db).Select("product_questions.*, products.store_id")
+}
+
+func visibleQuestions(db *gorm.DB) *gorm.DB {
+	return db.Where("product_questions.hidden_at IS NULL")
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/product"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SelectRelatedProducts returns the active, published related products of a
+// published product, best ranked first.
+func (r *productRepository) SelectRelatedProducts(id string, limit int) ([]entity.Product, error) {
+	var count int64
+	if err := r.db.Model(&model.Product{}).Scopes(publishedProducts).Where("id = ?", id).Count(&count).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		return nil, apperror.ErrProductNotFound
+	}
+
+	var productModels []model.Product
+	if err := r.db.Scopes(activeProducts, publishedProducts, preloadImages, preloadAttributes, withProductStats).
+		Joins("JOIN related_products rp ON rp.related_product_id = products.id").
+		Where("rp.product_id = ?", id).
+		Order("rp.score DESC, products.id").
+		Limit(limit).
+		Find(&productModels).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch related products",
+			Err:     err,
+		}
+	}
+
+	return convertProductsToEntity(productModels), nil
+}
+
+// ClaimRelatedRefreshes marks up to limit active published products whose related
+// products were computed before staleBefore, or never, as refreshed and
+// returns them. Products claimed by another replica are skipped.
+func (r *productRepository) ClaimRelatedRefreshes(
+	staleBefore time.Time,
+	limit int,
+) ([]product.RelatedCandidate, error) {
+	var ids []uint
+	if err := r.db.Raw(`
+		INSERT INTO related_product_refreshes (product_id, refreshed_at)
+		SELECT p.id, ? FROM products p
+		LEFT JOIN related_product_refreshes rr ON rr.product_id = p.id
+		WHERE p.archived_at IS NULL AND p.status = ? AND (rr.refreshed_at IS NULL OR rr.refreshed_at < ?)
+		ORDER BY rr.refreshed_at NULLS FIRST, p.id
+		LIMIT ?
+		ON CONFLICT (product_id) DO UPDATE SET refreshed_at = EXCLUDED.refreshed_at
+		WHERE related_product_refreshes.refreshed_at < ?
+		RETURNING product_id`,
+		time.Now(), product.StatusPublished, staleBefore, limit, staleBefore,
+	).Scan(&ids).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to claim related product refreshes",
+			Err:     err,
+		}
+	}
+
+	return r.selectRelatedCandidates(ids, nil)
+}
+
+// SelectRelatedCandidates returns the products that share a signal with the
+// source: the same category, closest in price first, a name matching one of
+// terms, or buyers who made offers on both. Each signal adds up to limit
+// candidates.
+func (r *productRepository) SelectRelatedCandidates(
+	source product.RelatedCandidate,
+	terms []string,
+	limit int,
+) ([]product.RelatedCandidate, error) {
+	var coOffers []struct {
+		ProductID uint
+		CoOffers  int
+	}
+	if err := r.db.Raw(`
+		SELECT o2.product_id, COUNT(DISTINCT o2.user_id) AS co_offers
+		FROM offers o1
+		JOIN offers o2 ON o2.user_id = o1.user_id AND o2.product_id <> o1.product_id
+		WHERE o1.product_id = ?
+		GROUP BY o2.product_id
+		ORDER BY co_offers DESC, o2.product_id
+		LIMIT ?`,
+		source.ID, limit,
+	).Scan(&coOffers).Error; err != nil {
+		return nil, relatedCandidatesError(err)
+	}
+
+	coOfferCounts := make(map[uint]int, len(coOffers))
+	ids := make([]uint, 0, len(coOffers))
+	for _, c := range coOffers {
+		coOfferCounts[c.ProductID] = c.CoOffers
+		ids = append(ids, c.ProductID)
+	}
+
+	if source.Category != "" {
+		var categoryIDs []uint
+		if err := r.db.Model(&model.Product{}).
+			Scopes(activeProducts, publishedProducts).
+			Where("category = ? AND id <> ?", source.Category, source.ID).
+			Order(clause.OrderBy{Expression: clause.NamedExpr{
+				SQL: "ABS(" + productBasePrice + " - " + inBaseCurrency("@price", "@currency") + "), id",
+				Vars: []interface{}{
+					sql.Named("price", source.Price),
+					sql.Named("currency", source.Price.Currency),
+				},
+			}}).
+			Limit(limit).
+			Pluck("id", &categoryIDs).Error; err != nil {
+			return nil, relatedCandidatesError(err)
+		}
+		ids = append(ids, categoryIDs...)
+	}
+
+	if len(terms) > 0 {
+		// Terms are letters and digits only, so they are safe tsquery lexemes
+		query := strings.Join(terms, " | ")
+
+		var textIDs []uint
+		if err := r.db.Model(&model.Product{}).
+			Scopes(activeProducts, publishedProducts).
+			Where("to_tsvector('simple', name) @@ to_tsquery('simple', ?) AND id <> ?", query, source.ID).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "ts_rank(to_tsvector('simple', name), to_tsquery('simple', ?)) DESC, id",
+				Vars: []interface{}{query},
+			}}).
+			Limit(limit).
+			Pluck("id", &textIDs).Error; err != nil {
+			return nil, relatedCandidatesError(err)
+		}
+		ids = append(ids, textIDs...)
+	}
+
+	return r.selectRelatedCandidates(ids, coOfferCounts)
+}
+
+// ReplaceRelatedProducts stores the newly ranked related products of the
+// product in place of the previous ones.
+func (r *productRepository) ReplaceRelatedProducts(productID uint, related []product.RelatedProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&model.RelatedProduct{}).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete related products",
+				Err:     err,
+			}
+		}
+		if len(related) == 0 {
+			return nil
+		}
+
+		relatedModels := make([]model.RelatedProduct, 0, len(related))
+		for _, rp := range related {
+			relatedModels = append(relatedModels, model.RelatedProduct{
+				ProductID:        productID,
+				RelatedProductID: rp.RelatedProductID,
+				Score:            rp.Score,
+			})
+		}
+		if err := tx.Create(&relatedModels).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to save related products",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *productRepository) selectRelatedCandidates(
+	ids []uint,
+	coOfferCounts map[uint]int,
+) ([]product.RelatedCandidate, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var productModels []model.Product
+	if err := r.db.Scopes(activeProducts, publishedProducts).
+		Select("id", "name", "description", "category", "price", "currency").
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&productModels).Error; err != nil {
+		return nil, relatedCandidatesError(err)
+	}
+
+	candidates := make([]product.RelatedCandidate, 0, len(productModels))
+	for _, p := range productModels {
+		candidate := model.ConvertProductToRelatedCandidate(p)
+		candidate.CoOffers = coOfferCounts[p.ID]
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+func relatedCandidatesError(err error) error {
+	return &apperror.ProductError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to fetch related product candidates",
+		Err:     err,
+	}
+}
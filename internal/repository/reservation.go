@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AcceptOffer accepts the offer if it is still in the from status and
+// reserves one unit of the offered product (or variant), or every item of the
+// offered bundle, in a single transaction. The product rows are locked with
+// SELECT ... FOR UPDATE so concurrent accepts on the last unit can't oversell.
+func (r *offerRepository) AcceptOffer(offerID uint, from string, reservationTTL time.Duration) (entity.Offer, error) {
+	var accepted model.Offer
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		offerModel, err := transitionOffer(tx, offerID, from, offer.StatusAccepted)
+		if err != nil {
+			return err
+		}
+
+		items, err := offerItems(tx, offerModel)
+		if err != nil {
+			return err
+		}
+
+		expiresAt := time.Now().Add(reservationTTL)
+		for _, item := range items {
+			if err := reserveStock(tx, item.ProductID, item.VariantID, item.Quantity); err != nil {
+				return err
+			}
+
+			reservation := model.StockReservation{
+				OfferID:   offerModel.ID,
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+				Status:    model.ReservationActive,
+				ExpiresAt: expiresAt,
+			}
+			if err := tx.Create(&reservation).Error; err != nil {
+				return &apperror.OfferError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to create stock reservation",
+					Err:     err,
+				}
+			}
+		}
+
+		accepted = offerModel
+		return nil
+	})
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	return model.ConvertOfferToEntity(accepted), nil
+}
+
+// ReleaseExpiredReservations cancels up to limit accepted offers whose stock
+// reservation expired, returns their stock and notifies the buyer and the
+// store staff of each. A cancelled offer can't be completed without the stock
+// it no longer holds. Offers locked by another replica are skipped.
+func (r *offerRepository) ReleaseExpiredReservations(now time.Time, limit int) (int, error) {
+	var offerIDs []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&model.StockReservation{}).
+			Select("offer_id").
+			Where("status = ? AND expires_at < ?", model.ReservationActive, now)
+		if err := tx.Model(&model.Offer{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND id IN (?)", offer.StatusAccepted, expired).
+			Order("id").
+			Limit(limit).
+			Pluck("id", &offerIDs).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch offers with expired reservations",
+				Err:     err,
+			}
+		}
+
+		for _, offerID := range offerIDs {
+			if _, err := releaseOfferReservation(tx, offerID, model.ReservationExpired); err != nil {
+				return err
+			}
+
+			offerModel, err := transitionOffer(tx, offerID, offer.StatusAccepted, offer.StatusCancelled)
+			if err != nil {
+				return err
+			}
+
+			memberIDs, err := storeMemberIDs(tx, offerModel.StoreID)
+			if err != nil {
+				return err
+			}
+			message := fmt.Sprintf("Offer %d was cancelled, its stock reservation expired", offerID)
+			if err := notifyOffer(tx, append([]uint{offerModel.UserID}, memberIDs...), offerID, message); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(offerIDs), nil
+}
+
+// offerItems returns what accepting the offer reserves: the items of the
+// offered bundle, or a single unit of the offered product.
+func offerItems(tx *gorm.DB, offerModel model.Offer) ([]model.BundleItem, error) {
+	if offerModel.BundleID != nil {
+		return bundleItems(tx, *offerModel.BundleID)
+	}
+
+	return []model.BundleItem{{
+		ProductID: *offerModel.ProductID,
+		VariantID: offerModel.VariantID,
+		Quantity:  1,
+	}}, nil
+}
+
+func reserveStock(tx *gorm.DB, productID uint, variantID *uint, quantity int) error {
+	locking := clause.Locking{Strength: "UPDATE"}
+
+	if variantID != nil {
+		var variant model.ProductVariant
+		if err := tx.Clauses(locking).Where("id = ?", *variantID).First(&variant).Error; err != nil {
+			return stockLockError(err)
+		}
+		if variant.Stock < quantity {
+			return apperror.ErrOfferOutOfStock
+		}
+		if err := adjustStock(tx, &model.ProductVariant{ID: variant.ID}, "stock", -quantity); err != nil {
+			return err
+		}
+		return bumpProductVersion(tx, productID)
+	}
+
+	var productModel model.Product
+	if err := tx.Clauses(locking).Where("id = ?", productID).First(&productModel).Error; err != nil {
+		return stockLockError(err)
+	}
+	if productModel.Quantity < quantity {
+		return apperror.ErrOfferOutOfStock
+	}
+	if err := adjustStock(tx, &model.Product{ID: productModel.ID}, "quantity", -quantity); err != nil {
+		return err
+	}
+	return bumpProductVersion(tx, productID)
+}
+
+// releaseOfferReservation settles the active reservations of an offer, one
+// per bundle item, and returns how many there were. A fulfilled reservation
+// keeps its stock; any other status returns it.
+func releaseOfferReservation(tx *gorm.DB, offerID uint, status string) (int, error) {
+	var reservations []model.StockReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("offer_id = ? AND status = ?", offerID, model.ReservationActive).
+		Order("id").
+		Find(&reservations).Error; err != nil {
+		return 0, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to lock stock reservation",
+			Err:     err,
+		}
+	}
+
+	for _, reservation := range reservations {
+		var err error
+		if status == model.ReservationFulfilled {
+			err = setReservationStatus(tx, reservation, status)
+		} else {
+			err = releaseStock(tx, reservation, status)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(reservations), nil
+}
+
+// releaseStock returns the reserved stock. A variant reservation keeps the id
+// of its variant once the variant is deleted, its stock is then gone with the
+// variant and is not credited to the product.
+func releaseStock(tx *gorm.DB, reservation model.StockReservation, status string) error {
+	var err error
+	if reservation.VariantID != nil {
+		err = adjustStock(tx, &model.ProductVariant{ID: *reservation.VariantID}, "stock", reservation.Quantity)
+	} else {
+		err = adjustStock(tx, &model.Product{ID: reservation.ProductID}, "quantity", reservation.Quantity)
+	}
+	if err == nil {
+		err = bumpProductVersion(tx, reservation.ProductID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return setReservationStatus(tx, reservation, status)
+}
+
+func setReservationStatus(tx *gorm.DB, reservation model.StockReservation, status string) error {
+	if err := tx.Model(&reservation).Update("status", status).Error; err != nil {
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update stock reservation",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func adjustStock(tx *gorm.DB, row interface{}, column string, delta int) error {
+	if err := tx.Model(row).Update(column, gorm.Expr(column+" + ?", delta)).Error; err != nil {
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update stock",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func stockLockError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.ErrOfferProductNotFound
+	}
+	return &apperror.OfferError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to lock product stock",
+		Err:     err,
+	}
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/offer"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/review"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reviewStatsColumns selects the number of reviews of a product and their
+// average rating.
+const reviewStatsColumns = `(SELECT COUNT(*) FROM product_reviews r
+	WHERE r.product_id = products.id) AS review_count,
+	(SELECT COALESCE(ROUND(AVG(r.rating), 2), 0) FROM product_reviews r
+	WHERE r.product_id = products.id) AS rating_average`
+
+// reviewOrders maps the review list orders to ORDER BY clauses
+var reviewOrders = map[string]string{
+	review.SortNewest:     "created_at DESC, id DESC",
+	review.SortOldest:     "created_at, id",
+	review.SortRatingHigh: "rating DESC, created_at DESC, id DESC",
+	review.SortRatingLow:  "rating, created_at DESC, id DESC",
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *reviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// InsertReview stores the review when the user has an accepted (or already
+// completed) offer on the product or a bundle holding it, the offer is kept as
+// proof of purchase.
+func (r *reviewRepository) InsertReview(rv review.Review) (entity.ProductReview, error) {
+	reviewModel := model.ConvertReviewFromSvc(rv)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var productModel model.Product
+		if err := tx.Scopes(activeProducts, publishedProducts).
+			Select("id").
+			Where("id = ?", rv.ProductID).
+			First(&productModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrReviewProductNotFound
+			}
+			return &apperror.ReviewError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch review product",
+				Err:     err,
+			}
+		}
+
+		var offerModel model.Offer
+		if err := tx.Select("id").
+			Where("user_id = ?", rv.UserID).
+			Where("product_id = ? OR bundle_id IN (?)", rv.ProductID,
+				tx.Model(&model.BundleItem{}).Select("bundle_id").Where("product_id = ?", rv.ProductID)).
+			Where("status IN ?", []string{offer.StatusAccepted, offer.StatusCompleted}).
+			Order("id").
+			First(&offerModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrReviewNotVerified
+			}
+			return &apperror.ReviewError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch accepted offer",
+				Err:     err,
+			}
+		}
+		reviewModel.OfferID = offerModel.ID
+
+		if err := tx.Create(&reviewModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return apperror.ErrReviewExists
+			}
+			return &apperror.ReviewError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create review",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	return model.ConvertReviewToEntity(reviewModel), nil
+}
+
+func (r *reviewRepository) GetReview(reviewID uint) (entity.ProductReview, error) {
+	reviewModel, err := selectReview(r.db, reviewID)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	return model.ConvertReviewToEntity(reviewModel), nil
+}
+
+// DeleteReview removes the review and returns it with the photos whose
+// objects are left to delete.
+func (r *reviewRepository) DeleteReview(reviewID uint) (entity.ProductReview, error) {
+	var reviewModel model.ProductReview
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if reviewModel, err = selectReview(tx.Clauses(clause.Locking{Strength: "UPDATE"}), reviewID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&model.ProductReview{ID: reviewID}).Error; err != nil {
+			return &apperror.ReviewError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete review",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+
+	return model.ConvertReviewToEntity(reviewModel), nil
+}
+
+func (r *reviewRepository) SelectProductReviews(
+	productID uint,
+	sort string,
+	offset, limit int,
+) ([]entity.ProductReview, int, error) {
+	var total int64
+	if err := r.db.Model(&model.ProductReview{}).
+		Where("product_id = ?", productID).
+		Count(&total).Error; err != nil {
+		return nil, 0, &apperror.ReviewError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count reviews",
+			Err:     err,
+		}
+	}
+
+	var reviewModels []model.ProductReview
+	if err := r.db.Scopes(preloadReviewPhotos).
+		Where("product_id = ?", productID).
+		Order(reviewOrders[sort]).
+		Offset(offset).
+		Limit(limit).
+		Find(&reviewModels).Error; err != nil {
+		return nil, 0, &apperror.ReviewError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch reviews",
+			Err:     err,
+		}
+	}
+
+	reviews := make([]entity.ProductReview, 0, len(reviewModels))
+	for _, rv := range reviewModels {
+		reviews = append(reviews, model.ConvertReviewToEntity(rv))
+	}
+
+	return reviews, int(total), nil
+}
+
+// InsertReviewPhoto appends the photo to the end of the review gallery.
+func (r *reviewRepository) InsertReviewPhoto(photo review.ReviewPhoto) (entity.ReviewPhoto, error) {
+	photoModel := model.ConvertReviewPhotoFromSvc(photo)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the review so concurrent uploads can't exceed the photo limit
+		if _, err := selectReview(tx.Clauses(clause.Locking{Strength: "UPDATE"}), photo.ReviewID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.ProductReviewPhoto{}).
+			Where("review_id = ?", photo.ReviewID).
+			Count(&count).Error; err != nil {
+			return &apperror.ReviewError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count review photos",
+				Err:     err,
+			}
+		}
+		if count >= review.MaxPhotos {
+			return apperror.ErrReviewPhotoLimit
+		}
+
+		photoModel.Position = int(count)
+		if err := tx.Create(&photoModel).Error; err != nil {
+			return &apperror.ReviewError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create review photo",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ReviewPhoto{}, err
+	}
+
+	return model.ConvertReviewPhotoToEntity(photoModel), nil
+}
+
+func (r *reviewRepository) DeleteReviewPhoto(reviewID, photoID uint) (entity.ReviewPhoto, error) {
+	var photoModel model.ProductReviewPhoto
+	result := r.db.Clauses(clause.Returning{}).
+		Where("id = ? AND review_id = ?", photoID, reviewID).
+		Delete(&photoModel)
+	if result.Error != nil {
+		return entity.ReviewPhoto{}, &apperror.ReviewError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to delete review photo",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return entity.ReviewPhoto{}, apperror.ErrReviewPhotoNotFound
+	}
+
+	return model.ConvertReviewPhotoToEntity(photoModel), nil
+}
+
+func selectReview(db *gorm.DB, reviewID uint) (model.ProductReview, error) {
+	var reviewModel model.ProductReview
+	if err := db.Scopes(preloadReviewPhotos).
+		Where("id = ?", reviewID).
+		First(&reviewModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ProductReview{}, apperror.ErrReviewNotFound
+		}
+		return model.ProductReview{}, &apperror.ReviewError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch review",
+			Err:     err,
+		}
+	}
+
+	return reviewModel, nil
+}
+
+func preloadReviewPhotos(db *gorm.DB) *gorm.DB {
+	return db.Preload("Photos", orderImages)
+}
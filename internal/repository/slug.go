@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"strconv"
+
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+	"github.com/PosokhovVadim/stawberry/pkg/slug"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetProductBySlug finds the product by its current slug or by one it had
+// before a rename, the slug of the result tells the two apart.
+func (r *productRepository) GetProductBySlug(productSlug string) (entity.Product, error) {
+	return r.getProduct(
+		"products.slug = ? OR products.id = (SELECT product_id FROM product_slug_redirects WHERE slug = ?)",
+		productSlug, productSlug,
+	)
+}
+
+// GetStoreIDBySlug resolves the slug of a store.
+func (r *productRepository) GetStoreIDBySlug(storeSlug string) (uint, error) {
+	var ids []uint
+	if err := r.db.Model(&model.Store{}).Where("slug = ?", storeSlug).Pluck("id", &ids).Error; err != nil {
+		return 0, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store",
+			Err:     err,
+		}
+	}
+	if len(ids) == 0 {
+		return 0, apperror.ErrStoreNotFound
+	}
+
+	return ids[0], nil
+}
+
+// uniqueProductSlug builds the slug of a product called name that no other
+// product holds, neither as its slug nor as a redirect from a former one.
+// Collisions get a numeric suffix: name, name-2, name-3 and so on.
+func uniqueProductSlug(tx *gorm.DB, productID uint, name string) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "product"
+	}
+
+	var taken []string
+	if err := tx.Raw(`SELECT slug FROM products
+		WHERE (slug = @base OR slug LIKE @prefix) AND id <> @id
+		UNION SELECT slug FROM product_slug_redirects
+		WHERE (slug = @base OR slug LIKE @prefix) AND product_id <> @id`,
+		map[string]interface{}{"base": base, "prefix": base + "-%", "id": productID},
+	).Scan(&taken).Error; err != nil {
+		return "", &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product slugs",
+			Err:     err,
+		}
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+
+	candidate := base
+	for n := 2; used[candidate]; n++ {
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+
+	return candidate, nil
+}
+
+// renameProductSlug gives a renamed product the slug of its new name and
+// keeps the old slug as a redirect, a former slug of the product is reclaimed.
+// It returns the slug the product should have.
+func renameProductSlug(tx *gorm.DB, productID uint, oldSlug, name string) (string, error) {
+	newSlug, err := uniqueProductSlug(tx, productID, name)
+	if err != nil || newSlug == oldSlug {
+		return oldSlug, err
+	}
+
+	if err := tx.Where("slug = ? AND product_id = ?", newSlug, productID).
+		Delete(&model.ProductSlugRedirect{}).Error; err != nil {
+		return "", &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to reclaim product slug",
+			Err:     err,
+		}
+	}
+
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ProductSlugRedirect{Slug: oldSlug, ProductID: productID}).Error; err != nil {
+		return "", &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save product slug redirect",
+			Err:     err,
+		}
+	}
+
+	return newSlug, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+type storeRepository struct {
+	db *gorm.DB
+}
+
+func NewStoreRepository(db *gorm.DB) *storeRepository {
+	return &storeRepository{db: db}
+}
+
+// IsStoreMember reports whether the user belongs to the store. When roles are
+// given the membership must have one of them.
+func (r *storeRepository) IsStoreMember(storeID, userID uint, roles ...string) (bool, error) {
+	query := r.db.Model(&model.StoreMember{}).Where("store_id = ? AND user_id = ?", storeID, userID)
+	if len(roles) > 0 {
+		query = query.Where("role IN ?", roles)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetStoreCurrency returns the currency the store prices and settles offers
+// in, or an empty string if the store doesn't exist.
+func (r *storeRepository) GetStoreCurrency(storeID uint) (string, error) {
+	var currencies []string
+	if err := r.db.Model(&model.Store{}).Where("id = ?", storeID).Pluck("currency", &currencies).Error; err != nil {
+		return "", err
+	}
+	if len(currencies) == 0 {
+		return "", nil
+	}
+
+	return currencies[0], nil
+}
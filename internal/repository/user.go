@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *userRepository {
+	return &userRepository{db: db}
+}
+
+// IsAdmin reports whether the user is a marketplace administrator.
+func (r *userRepository) IsAdmin(userID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.User{}).Where("id = ? AND is_admin", userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
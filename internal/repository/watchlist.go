@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"github.com/PosokhovVadim/stawberry/internal/app/apperror"
+	"github.com/PosokhovVadim/stawberry/internal/domain/entity"
+	"github.com/PosokhovVadim/stawberry/internal/domain/service/watchlist"
+	"github.com/PosokhovVadim/stawberry/internal/repository/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// watcherCountColumn selects the number of users watching a product.
+const watcherCountColumn = `(SELECT COUNT(*) FROM watchlist_items w
+	WHERE w.product_id = products.id) AS watcher_count`
+
+type watchlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchlistRepository(db *gorm.DB) *watchlistRepository {
+	return &watchlistRepository{db: db}
+}
+
+// InsertWatchlistItem adds the item unless the user already watches it.
+// Archived products can't be added.
+func (r *watchlistRepository) InsertWatchlistItem(item watchlist.Item) error {
+	var count int64
+	var err error
+	if item.ProductID != nil {
+		err = r.db.Model(&model.Product{}).
+			Scopes(activeProducts, publishedProducts).
+			Where("id = ?", *item.ProductID).
+			Count(&count).Error
+	} else {
+		err = r.db.Model(&model.Store{}).Where("id = ?", *item.StoreID).Count(&count).Error
+	}
+	if err != nil {
+		return &apperror.WatchlistError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check watched item",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		if item.ProductID != nil {
+			return apperror.ErrWatchlistProductNotFound
+		}
+		return apperror.ErrWatchlistStoreNotFound
+	}
+
+	itemModel := model.ConvertWatchlistItemFromSvc(item)
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&itemModel).Error; err != nil {
+		return &apperror.WatchlistError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to add watchlist item",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (r *watchlistRepository) DeleteWatchlistItem(item watchlist.Item) error {
+	query := r.db.Where("user_id = ?", item.UserID)
+	if item.ProductID != nil {
+		query = query.Where("product_id = ?", *item.ProductID)
+	} else {
+		query = query.Where("store_id = ?", *item.StoreID)
+	}
+
+	result := query.Delete(&model.WatchlistItem{})
+	if result.Error != nil {
+		return &apperror.WatchlistError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to remove watchlist item",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrWatchlistItemNotFound
+	}
+
+	return nil
+}
+
+func (r *watchlistRepository) SelectWatchlist(
+	userID uint,
+	itemType string,
+	offset, limit int,
+) ([]entity.WatchlistItem, int, error) {
+	var total int64
+	if err := r.db.Model(&model.WatchlistItem{}).
+		Scopes(watchlistOf(userID, itemType)).
+		Count(&total).Error; err != nil {
+		return nil, 0, &apperror.WatchlistError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count watchlist items",
+			Err:     err,
+		}
+	}
+
+	var itemModels []model.WatchlistItem
+	if err := r.db.Scopes(watchlistOf(userID, itemType)).
+		Preload("Product").
+		Preload("Store").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&itemModels).Error; err != nil {
+		return nil, 0, &apperror.WatchlistError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch watchlist",
+			Err:     err,
+		}
+	}
+
+	items := make([]entity.WatchlistItem, 0, len(itemModels))
+	for _, item := range itemModels {
+		items = append(items, model.ConvertWatchlistItemToEntity(item))
+	}
+
+	return items, int(total), nil
+}
+
+func watchlistOf(userID uint, itemType string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		switch itemType {
+		case watchlist.ItemProduct:
+			db = db.Where("product_id IS NOT NULL")
+		case watchlist.ItemStore:
+			db = db.Where("store_id IS NOT NULL")
+		}
+		return db
+	}
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type ImportService interface {
+	ProcessPendingImports(ctx context.Context, limit int) (int, error)
+}
+
+type catalogImporter struct {
+	importService ImportService
+	interval      time.Duration
+	batchSize     int
+}
+
+func NewCatalogImporter(importService ImportService, interval time.Duration, batchSize int) *catalogImporter {
+	return &catalogImporter{
+		importService: importService,
+		interval:      interval,
+		batchSize:     batchSize,
+	}
+}
+
+// Run periodically applies queued catalog imports until ctx is done.
+func (w *catalogImporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.processPending(ctx)
+		}
+	}
+}
+
+func (w *catalogImporter) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.importService.ProcessPendingImports(ctx, w.batchSize)
+		if err != nil {
+			log.Printf("Failed to process pending catalog imports: %v", err)
+			return
+		}
+		if processed < w.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type ImageService interface {
+	ProcessPendingImages(ctx context.Context, limit int) (int, error)
+}
+
+type imageProcessor struct {
+	imageService ImageService
+	interval     time.Duration
+	batchSize    int
+}
+
+func NewImageProcessor(imageService ImageService, interval time.Duration, batchSize int) *imageProcessor {
+	return &imageProcessor{
+		imageService: imageService,
+		interval:     interval,
+		batchSize:    batchSize,
+	}
+}
+
+// Run periodically generates renditions of uploaded product images until ctx is done.
+func (w *imageProcessor) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.processPending(ctx)
+		}
+	}
+}
+
+func (w *imageProcessor) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.imageService.ProcessPendingImages(ctx, w.batchSize)
+		if err != nil {
+			log.Printf("Failed to process pending images: %v", err)
+			return
+		}
+		if processed < w.batchSize {
+			return
+		}
+	}
+}
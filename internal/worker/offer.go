@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type OfferExpirationService interface {
+	ExpireOffers(now time.Time, limit int) (int, error)
+}
+
+// defaultOfferExpireBatchSize is used when the configured batch size is not
+// positive, a batch of zero would never drain the due offers.
+const defaultOfferExpireBatchSize = 100
+
+type offerExpirer struct {
+	offerService OfferExpirationService
+	interval     time.Duration
+	batchSize    int
+}
+
+func NewOfferExpirer(
+	offerService OfferExpirationService,
+	interval time.Duration,
+	batchSize int,
+) *offerExpirer {
+	if batchSize <= 0 {
+		batchSize = defaultOfferExpireBatchSize
+	}
+	return &offerExpirer{
+		offerService: offerService,
+		interval:     interval,
+		batchSize:    batchSize,
+	}
+}
+
+// Run periodically expires the offers left unanswered past their expiration
+// until ctx is done.
+func (w *offerExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.expireDue(ctx)
+		}
+	}
+}
+
+func (w *offerExpirer) expireDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		expired, err := w.offerService.ExpireOffers(time.Now(), w.batchSize)
+		if err != nil {
+			log.Printf("Failed to expire offers: %v", err)
+			return
+		}
+		if expired > 0 {
+			log.Printf("Expired %d offers", expired)
+		}
+		if expired == 0 || expired < w.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type PublicationService interface {
+	PublishScheduledProducts(now time.Time, limit int) (int, error)
+}
+
+type productPublisher struct {
+	publicationService PublicationService
+	interval           time.Duration
+	batchSize          int
+}
+
+func NewProductPublisher(
+	publicationService PublicationService,
+	interval time.Duration,
+	batchSize int,
+) *productPublisher {
+	return &productPublisher{
+		publicationService: publicationService,
+		interval:           interval,
+		batchSize:          batchSize,
+	}
+}
+
+// Run periodically publishes the scheduled products that are due until ctx
+// is done.
+func (w *productPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.publishDue(ctx)
+		}
+	}
+}
+
+func (w *productPublisher) publishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := w.publicationService.PublishScheduledProducts(time.Now(), w.batchSize)
+		if err != nil {
+			log.Printf("Failed to publish scheduled products: %v", err)
+			return
+		}
+		if published > 0 {
+			log.Printf("Published %d scheduled products", published)
+		}
+		if published < w.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type RelatedService interface {
+	RefreshRelatedProducts(ctx context.Context, staleBefore time.Time, limit int) (int, error)
+}
+
+type relatedRefresher struct {
+	relatedService RelatedService
+	interval       time.Duration
+	batchSize      int
+}
+
+func NewRelatedRefresher(relatedService RelatedService, interval time.Duration, batchSize int) *relatedRefresher {
+	return &relatedRefresher{
+		relatedService: relatedService,
+		interval:       interval,
+		batchSize:      batchSize,
+	}
+}
+
+// Run recomputes related products on start and then once per interval until
+// ctx is done.
+func (w *relatedRefresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.refresh(ctx)
+		}
+	}
+}
+
+func (w *relatedRefresher) refresh(ctx context.Context) {
+	// Half an interval refreshes everything once per tick while skipping
+	// products another replica refreshed during its own, offset tick
+	staleBefore := time.Now().Add(-w.interval / 2)
+
+	refreshed := 0
+	for ctx.Err() == nil {
+		processed, err := w.relatedService.RefreshRelatedProducts(ctx, staleBefore, w.batchSize)
+		if err != nil {
+			log.Printf("Failed to refresh related products: %v", err)
+			return
+		}
+		refreshed += processed
+		if processed < w.batchSize {
+			break
+		}
+	}
+	if refreshed > 0 {
+		log.Printf("Refreshed related products of %d products", refreshed)
+	}
+}
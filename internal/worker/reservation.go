@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type ReservationService interface {
+	ReleaseExpiredReservations(now time.Time, limit int) (int, error)
+}
+
+type reservationReleaser struct {
+	reservationService ReservationService
+	interval           time.Duration
+	batchSize          int
+}
+
+func NewReservationReleaser(
+	reservationService ReservationService,
+	interval time.Duration,
+	batchSize int,
+) *reservationReleaser {
+	return &reservationReleaser{
+		reservationService: reservationService,
+		interval:           interval,
+		batchSize:          batchSize,
+	}
+}
+
+// Run periodically cancels the accepted offers whose stock reservation expired
+// until ctx is done.
+func (w *reservationReleaser) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.releaseExpired(ctx)
+		}
+	}
+}
+
+func (w *reservationReleaser) releaseExpired(ctx context.Context) {
+	for ctx.Err() == nil {
+		released, err := w.reservationService.ReleaseExpiredReservations(time.Now(), w.batchSize)
+		if err != nil {
+			log.Printf("Failed to cancel offers with expired reservations: %v", err)
+			return
+		}
+		if released > 0 {
+			log.Printf("Cancelled %d offers with expired reservations", released)
+		}
+		if released < w.batchSize {
+			return
+		}
+	}
+}
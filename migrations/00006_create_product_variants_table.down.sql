@@ -0,0 +1,3 @@
+ALTER TABLE offers DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variants;
\ No newline at end of file
@@ -0,0 +1,23 @@
+CREATE TABLE product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku TEXT NOT NULL,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    price NUMERIC(10, 2) NOT NULL,
+    stock INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (product_id, sku)
+);
+
+-- Index on product_id
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+
+-- A variant that offers or bundles were made on can't be deleted, so an offer
+-- never silently moves to another variant or to the whole product. NO ACTION
+-- rather than RESTRICT still lets a deleted product take its variants along
+-- with its offers.
+ALTER TABLE offers ADD COLUMN variant_id INTEGER REFERENCES product_variants(id) ON DELETE NO ACTION;
+
+-- Index on variant_id
+CREATE INDEX idx_offers_variant_id ON offers(variant_id);
\ No newline at end of file
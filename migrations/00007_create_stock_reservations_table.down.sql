@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS stock_reservations;
+
+ALTER TABLE product_variants DROP CONSTRAINT IF EXISTS chk_product_variants_stock;
+
+ALTER TABLE products DROP COLUMN in_stock;
+ALTER TABLE products ADD COLUMN in_stock BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE products SET in_stock = quantity > 0;
+ALTER TABLE products DROP COLUMN quantity;
\ No newline at end of file
@@ -0,0 +1,27 @@
+ALTER TABLE products ADD COLUMN quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0);
+
+-- Products that were marked as in stock keep a single available unit
+UPDATE products SET quantity = 1 WHERE in_stock;
+
+ALTER TABLE products DROP COLUMN in_stock;
+ALTER TABLE products ADD COLUMN in_stock BOOLEAN GENERATED ALWAYS AS (quantity > 0) STORED;
+
+ALTER TABLE product_variants ADD CONSTRAINT chk_product_variants_stock CHECK (stock >= 0);
+
+CREATE TABLE stock_reservations (
+    id SERIAL PRIMARY KEY,
+    offer_id INTEGER NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status TEXT NOT NULL DEFAULT 'active', -- active, fulfilled, released, expired
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Only one active reservation per offer
+CREATE UNIQUE INDEX idx_stock_reservations_active_offer ON stock_reservations(offer_id) WHERE status = 'active';
+
+-- Index for the expiration sweep
+CREATE INDEX idx_stock_reservations_status_expires_at ON stock_reservations(status, expires_at);
\ No newline at end of file
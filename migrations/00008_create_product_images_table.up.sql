@@ -0,0 +1,16 @@
+CREATE TABLE product_images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index on product_id and position
+CREATE INDEX idx_product_images_product_id_position ON product_images(product_id, position);
+
+-- Only one primary image per product
+CREATE UNIQUE INDEX idx_product_images_primary ON product_images(product_id) WHERE is_primary;
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS product_image_renditions;
+
+DROP INDEX IF EXISTS idx_product_images_processing_status;
+ALTER TABLE product_images DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE product_images DROP COLUMN IF EXISTS attempts;
+ALTER TABLE product_images DROP COLUMN IF EXISTS processing_status;
\ No newline at end of file
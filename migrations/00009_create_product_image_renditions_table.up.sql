@@ -0,0 +1,19 @@
+ALTER TABLE product_images ADD COLUMN processing_status TEXT NOT NULL DEFAULT 'pending'; -- pending, processing, ready, failed
+ALTER TABLE product_images ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE product_images ADD COLUMN claimed_at TIMESTAMP;
+
+-- Index for the processing queue
+CREATE INDEX idx_product_images_processing_status ON product_images(processing_status, id);
+
+CREATE TABLE product_image_renditions (
+    id SERIAL PRIMARY KEY,
+    image_id INTEGER NOT NULL REFERENCES product_images(id) ON DELETE CASCADE,
+    name TEXT NOT NULL, -- thumbnail, medium, large
+    object_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (image_id, name)
+);
\ No newline at end of file
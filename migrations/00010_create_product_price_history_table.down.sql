@@ -0,0 +1 @@
+DROP TABLE IF EXISTS product_price_history;
\ No newline at end of file
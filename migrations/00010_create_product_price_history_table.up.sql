@@ -0,0 +1,15 @@
+CREATE TABLE product_price_history (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    old_price NUMERIC(10, 2),
+    new_price NUMERIC(10, 2) NOT NULL,
+    changed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index on product_id and changed_at
+CREATE INDEX idx_product_price_history_product_id_changed_at ON product_price_history(product_id, changed_at);
+
+-- Existing products start their history with the current price
+INSERT INTO product_price_history (product_id, new_price, changed_at)
+SELECT id, price, updated_at FROM products;
\ No newline at end of file
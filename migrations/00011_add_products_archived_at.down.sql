@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_active_store_id;
+ALTER TABLE products DROP COLUMN IF EXISTS archived_at;
\ No newline at end of file
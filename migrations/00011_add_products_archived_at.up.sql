@@ -0,0 +1,4 @@
+ALTER TABLE products ADD COLUMN archived_at TIMESTAMP;
+
+-- Listings only read active products
+CREATE INDEX idx_products_active_store_id ON products(store_id) WHERE archived_at IS NULL;
\ No newline at end of file
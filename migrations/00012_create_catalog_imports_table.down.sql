@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS catalog_import_errors;
+DROP TABLE IF EXISTS catalog_imports;
+
+DROP INDEX IF EXISTS idx_products_store_id_external_sku;
+ALTER TABLE products DROP COLUMN IF EXISTS external_sku;
\ No newline at end of file
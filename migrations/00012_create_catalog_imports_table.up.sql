@@ -0,0 +1,40 @@
+ALTER TABLE products ADD COLUMN external_sku TEXT;
+
+-- External SKUs are unique within a store
+CREATE UNIQUE INDEX idx_products_store_id_external_sku ON products(store_id, external_sku) WHERE external_sku IS NOT NULL;
+
+CREATE TABLE catalog_imports (
+    id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    format TEXT NOT NULL, -- csv, ndjson
+    object_key TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, processing, completed, failed
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    succeeded_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    claimed_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index on store_id
+CREATE INDEX idx_catalog_imports_store_id ON catalog_imports(store_id);
+
+-- Index for the import queue
+CREATE INDEX idx_catalog_imports_status ON catalog_imports(status, id);
+
+CREATE TABLE catalog_import_errors (
+    id SERIAL PRIMARY KEY,
+    import_id INTEGER NOT NULL REFERENCES catalog_imports(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    external_sku TEXT,
+    message TEXT NOT NULL
+);
+
+-- Index on import_id and row_number
+CREATE INDEX idx_catalog_import_errors_import_id ON catalog_import_errors(import_id, row_number);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+ALTER TABLE offers DROP COLUMN IF EXISTS currency;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
+ALTER TABLE stores DROP COLUMN IF EXISTS currency;
\ No newline at end of file
@@ -0,0 +1,19 @@
+ALTER TABLE stores ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
+
+-- Products and offers inherit the currency of their store
+ALTER TABLE products ADD COLUMN currency TEXT;
+UPDATE products SET currency = stores.currency FROM stores WHERE stores.id = products.store_id;
+ALTER TABLE products ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE offers ADD COLUMN currency TEXT;
+UPDATE offers SET currency = stores.currency FROM stores WHERE stores.id = offers.store_id;
+ALTER TABLE offers ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Price of one unit of currency in the base currency (RUB)
+CREATE TABLE exchange_rates (
+    currency TEXT PRIMARY KEY,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
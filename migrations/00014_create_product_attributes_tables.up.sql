@@ -0,0 +1,24 @@
+CREATE TABLE category_attributes (
+    id SERIAL PRIMARY KEY,
+    category TEXT NOT NULL,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL, -- string, number, enum, boolean
+    options JSONB NOT NULL DEFAULT '[]', -- allowed values of enum attributes
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (category, key)
+);
+
+CREATE TABLE product_attribute_values (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    attribute_id INTEGER NOT NULL REFERENCES category_attributes(id) ON DELETE CASCADE,
+    value_text TEXT NOT NULL, -- canonical text of every type, used for matching and facets
+    value_number NUMERIC,
+    value_bool BOOLEAN,
+    PRIMARY KEY (product_id, attribute_id)
+);
+
+-- Indexes for attribute filters
+CREATE INDEX idx_product_attribute_values_text ON product_attribute_values(attribute_id, lower(value_text));
+CREATE INDEX idx_product_attribute_values_number ON product_attribute_values(attribute_id, value_number) WHERE value_number IS NOT NULL;
\ No newline at end of file
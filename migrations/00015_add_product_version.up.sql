@@ -0,0 +1,2 @@
+-- Incremented on every product edit, exposed as the ETag of the product
+ALTER TABLE products ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
\ No newline at end of file
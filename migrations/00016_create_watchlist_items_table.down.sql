@@ -0,0 +1 @@
+DROP TABLE IF EXISTS watchlist_items;
\ No newline at end of file
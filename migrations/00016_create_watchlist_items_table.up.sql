@@ -0,0 +1,19 @@
+CREATE TABLE watchlist_items (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    store_id INTEGER REFERENCES stores(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- An item watches either a product or a store
+    CONSTRAINT chk_watchlist_items_target CHECK ((product_id IS NULL) <> (store_id IS NULL))
+);
+
+-- A user watches a product or a store once
+CREATE UNIQUE INDEX idx_watchlist_items_user_product ON watchlist_items(user_id, product_id) WHERE product_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_watchlist_items_user_store ON watchlist_items(user_id, store_id) WHERE store_id IS NOT NULL;
+
+-- Index on user_id and created_at for listing a watchlist
+CREATE INDEX idx_watchlist_items_user_id ON watchlist_items(user_id, created_at DESC);
+
+-- Index on product_id for watcher counts
+CREATE INDEX idx_watchlist_items_product_id ON watchlist_items(product_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS price_alerts;
+
+DELETE FROM notifications WHERE offer_id IS NULL;
+ALTER TABLE notifications DROP COLUMN IF EXISTS product_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS type;
+ALTER TABLE notifications ALTER COLUMN offer_id SET NOT NULL;
\ No newline at end of file
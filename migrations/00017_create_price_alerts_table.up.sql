@@ -0,0 +1,21 @@
+-- Notifications are no longer only about offers
+ALTER TABLE notifications ALTER COLUMN offer_id DROP NOT NULL;
+ALTER TABLE notifications ADD COLUMN type TEXT NOT NULL DEFAULT 'offer'; -- offer, price_alert
+ALTER TABLE notifications ADD COLUMN product_id INTEGER REFERENCES products(id) ON DELETE CASCADE;
+
+CREATE TABLE price_alerts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    target_price NUMERIC(10, 2) NOT NULL CHECK (target_price > 0),
+    currency TEXT NOT NULL,
+    triggered_at TIMESTAMP, -- NULL while the alert is armed
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- A user has one alert per product
+CREATE UNIQUE INDEX idx_price_alerts_user_product ON price_alerts(user_id, product_id);
+
+-- Index for firing the armed alerts of a product
+CREATE INDEX idx_price_alerts_armed ON price_alerts(product_id, target_price) WHERE triggered_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_name_tsv;
+DROP TABLE IF EXISTS related_product_refreshes;
+DROP TABLE IF EXISTS related_products;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE related_products (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    related_product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (product_id, related_product_id)
+);
+
+-- Index for listing the related products of a product by rank
+CREATE INDEX idx_related_products_rank ON related_products(product_id, score DESC);
+
+-- When the related products of a product were last computed
+CREATE TABLE related_product_refreshes (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    refreshed_at TIMESTAMP NOT NULL
+);
+
+-- Full-text index on product names for text similarity candidates
+CREATE INDEX idx_products_name_tsv ON products USING GIN (to_tsvector('simple', name));
\ No newline at end of file
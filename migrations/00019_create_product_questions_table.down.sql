@@ -0,0 +1,3 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS question_id;
+
+DROP TABLE IF EXISTS product_questions;
\ No newline at end of file
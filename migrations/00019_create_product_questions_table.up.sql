@@ -0,0 +1,18 @@
+CREATE TABLE product_questions (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question TEXT NOT NULL,
+    answer TEXT,
+    answered_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    answered_at TIMESTAMP,
+    hidden_at TIMESTAMP, -- set when an administrator hides the entry
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index for the public answered questions of a product
+CREATE INDEX idx_product_questions_product_id ON product_questions(product_id, answered_at DESC) WHERE hidden_at IS NULL;
+
+-- Question and answer notifications link to their question, type question or answer
+ALTER TABLE notifications ADD COLUMN question_id INTEGER REFERENCES product_questions(id) ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_review_photos;
+
+DROP TABLE IF EXISTS product_reviews;
\ No newline at end of file
@@ -0,0 +1,27 @@
+CREATE TABLE product_reviews (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offer_id INTEGER NOT NULL REFERENCES offers(id) ON DELETE CASCADE, -- the accepted offer proving the purchase
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, product_id)
+);
+
+-- Index on product_id and created_at
+CREATE INDEX idx_product_reviews_product_id ON product_reviews(product_id, created_at DESC);
+
+CREATE TABLE product_review_photos (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL REFERENCES product_reviews(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index on review_id and position
+CREATE INDEX idx_product_review_photos_review_id_position ON product_review_photos(review_id, position);
\ No newline at end of file
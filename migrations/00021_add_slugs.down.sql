@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS product_slug_redirects;
+
+DROP TRIGGER IF EXISTS stores_generate_slug ON stores;
+
+DROP FUNCTION IF EXISTS generate_store_slug();
+
+ALTER TABLE stores DROP COLUMN IF EXISTS slug;
+
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
+
+DROP FUNCTION IF EXISTS unique_slug(REGCLASS, INTEGER, TEXT, TEXT);
+
+DROP FUNCTION IF EXISTS slugify(TEXT, TEXT);
\ No newline at end of file
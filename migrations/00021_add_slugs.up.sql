@@ -0,0 +1,80 @@
+-- Transliterates Cyrillic names into slugs the way pkg/slug does: lowercase
+-- latin letters and digits joined by single hyphens, cut to 80 characters
+CREATE FUNCTION slugify(name TEXT, fallback TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(NULLIF(btrim(left(btrim(regexp_replace(translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+            lower(name),
+            'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'), 'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'),
+            'ё', 'yo'), 'є', 'ye'), 'ї', 'yi'), 'ъ', ''), 'ь', ''),
+        'абвгдезийклмнопрстуфыэіґ', 'abvgdeziyklmnoprstufyeig'),
+        '[^a-z0-9]+', '-', 'g'), '-'), 80), '-'), ''), fallback)
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Returns the slug of name no other row of the table holds, collisions get a
+-- numeric suffix like in pkg/slug: name, name-2, name-3 and so on
+CREATE FUNCTION unique_slug(tbl REGCLASS, row_id INTEGER, name TEXT, fallback TEXT) RETURNS TEXT AS $$
+DECLARE
+    base TEXT := slugify(name, fallback);
+    candidate TEXT := base;
+    n INTEGER := 2;
+    taken BOOLEAN;
+BEGIN
+    LOOP
+        EXECUTE format('SELECT EXISTS (SELECT 1 FROM %s WHERE slug = $1 AND id <> $2)', tbl)
+            INTO taken USING candidate, row_id;
+        EXIT WHEN NOT taken;
+        candidate := base || '-' || n;
+        n := n + 1;
+    END LOOP;
+    RETURN candidate;
+END
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE products ADD COLUMN slug TEXT;
+
+ALTER TABLE products ADD CONSTRAINT products_slug_key UNIQUE (slug);
+
+ALTER TABLE stores ADD COLUMN slug TEXT;
+
+ALTER TABLE stores ADD CONSTRAINT stores_slug_key UNIQUE (slug);
+
+-- Existing rows get their slugs in id order, so the oldest keeps the bare name
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT id, name FROM products ORDER BY id LOOP
+        UPDATE products SET slug = unique_slug('products', r.id, r.name, 'product') WHERE id = r.id;
+    END LOOP;
+    FOR r IN SELECT id, name FROM stores ORDER BY id LOOP
+        UPDATE stores SET slug = unique_slug('stores', r.id, r.name, 'store') WHERE id = r.id;
+    END LOOP;
+END
+$$;
+
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+
+ALTER TABLE stores ALTER COLUMN slug SET NOT NULL;
+
+-- Stores are created outside the API, so their slug is generated on insert
+CREATE FUNCTION generate_store_slug() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.slug IS NULL OR NEW.slug = '' THEN
+        NEW.slug := unique_slug('stores', NEW.id, NEW.name, 'store');
+    END IF;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stores_generate_slug BEFORE INSERT ON stores
+    FOR EACH ROW EXECUTE FUNCTION generate_store_slug();
+
+-- Slugs a product had before a rename, they redirect to the current one
+CREATE TABLE product_slug_redirects (
+    slug TEXT PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index on product_id
+CREATE INDEX idx_product_slug_redirects_product_id ON product_slug_redirects(product_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE products DROP COLUMN IF EXISTS publish_at;
+
+ALTER TABLE products DROP COLUMN IF EXISTS status;
\ No newline at end of file
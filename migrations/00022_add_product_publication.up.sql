@@ -0,0 +1,10 @@
+ALTER TABLE products ADD COLUMN status TEXT NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'scheduled', 'published'));
+
+ALTER TABLE products ADD COLUMN publish_at TIMESTAMP; -- publication time, planned while scheduled
+
+UPDATE products SET publish_at = created_at;
+
+ALTER TABLE products ADD CONSTRAINT products_scheduled_publish_at_check CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+-- Index for the scheduler publishing due products
+CREATE INDEX idx_products_scheduled_publish_at ON products(publish_at) WHERE status = 'scheduled';
\ No newline at end of file
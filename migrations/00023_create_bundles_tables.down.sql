@@ -0,0 +1,32 @@
+-- Bundle offers hold several active reservations, return their stock and
+-- drop them before the single-reservation index comes back
+UPDATE product_variants v SET stock = v.stock + r.quantity
+FROM (
+    SELECT variant_id, SUM(quantity) AS quantity FROM stock_reservations
+    WHERE status = 'active' AND variant_id IS NOT NULL
+      AND offer_id IN (SELECT id FROM offers WHERE bundle_id IS NOT NULL)
+    GROUP BY variant_id
+) r
+WHERE v.id = r.variant_id;
+
+UPDATE products p SET quantity = p.quantity + r.quantity
+FROM (
+    SELECT product_id, SUM(quantity) AS quantity FROM stock_reservations
+    WHERE status = 'active' AND variant_id IS NULL
+      AND offer_id IN (SELECT id FROM offers WHERE bundle_id IS NOT NULL)
+    GROUP BY product_id
+) r
+WHERE p.id = r.product_id;
+
+DELETE FROM stock_reservations WHERE offer_id IN (SELECT id FROM offers WHERE bundle_id IS NOT NULL);
+DELETE FROM offers WHERE bundle_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_stock_reservations_active_offer;
+CREATE UNIQUE INDEX idx_stock_reservations_active_offer ON stock_reservations(offer_id) WHERE status = 'active';
+
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS chk_offers_target;
+ALTER TABLE offers ALTER COLUMN product_id SET NOT NULL;
+ALTER TABLE offers DROP COLUMN IF EXISTS bundle_id;
+
+DROP TABLE IF EXISTS bundle_items;
+DROP TABLE IF EXISTS bundles;
\ No newline at end of file
@@ -0,0 +1,37 @@
+CREATE TABLE bundles (
+    id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- the buyer who assembled it, NULL for store bundles
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index for the bundles a store offers
+CREATE INDEX idx_bundles_store_id ON bundles(store_id) WHERE user_id IS NULL;
+
+CREATE TABLE bundle_items (
+    id SERIAL PRIMARY KEY,
+    bundle_id INTEGER NOT NULL REFERENCES bundles(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE NO ACTION, -- can't be deleted, like offers
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+-- A product or variant appears once per bundle
+CREATE UNIQUE INDEX idx_bundle_items_bundle_product ON bundle_items(bundle_id, product_id, COALESCE(variant_id, 0));
+
+-- Index for the bundles containing a product
+CREATE INDEX idx_bundle_items_product_id ON bundle_items(product_id);
+
+-- An offer is made either on a single product or on a whole bundle
+ALTER TABLE offers ADD COLUMN bundle_id INTEGER REFERENCES bundles(id) ON DELETE CASCADE;
+ALTER TABLE offers ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE offers ADD CONSTRAINT chk_offers_target CHECK ((product_id IS NULL) <> (bundle_id IS NULL));
+
+-- Index on bundle_id
+CREATE INDEX idx_offers_bundle_id ON offers(bundle_id) WHERE bundle_id IS NOT NULL;
+
+-- A bundle offer holds one active reservation per item
+DROP INDEX IF EXISTS idx_stock_reservations_active_offer;
+CREATE UNIQUE INDEX idx_stock_reservations_active_offer ON stock_reservations(offer_id, product_id, COALESCE(variant_id, 0)) WHERE status = 'active';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_status_check;
\ No newline at end of file
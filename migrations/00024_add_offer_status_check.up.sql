@@ -0,0 +1,6 @@
+-- Offers left in an unknown status before transitions were validated are closed
+UPDATE offers SET status = 'rejected'
+WHERE status NOT IN ('pending', 'countered', 'accepted', 'rejected', 'expired', 'withdrawn', 'completed', 'cancelled');
+
+ALTER TABLE offers ADD CONSTRAINT offers_status_check
+CHECK (status IN ('pending', 'countered', 'accepted', 'rejected', 'expired', 'withdrawn', 'completed', 'cancelled'));
\ No newline at end of file
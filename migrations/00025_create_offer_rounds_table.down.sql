@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS offer_rounds;
+
+ALTER TABLE offers DROP COLUMN IF EXISTS max_rounds;
\ No newline at end of file
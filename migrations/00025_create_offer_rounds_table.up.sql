@@ -0,0 +1,17 @@
+ALTER TABLE offers ADD COLUMN max_rounds INTEGER NOT NULL DEFAULT 5 CHECK (max_rounds > 0);
+
+CREATE TABLE offer_rounds (
+    id SERIAL PRIMARY KEY,
+    offer_id INTEGER NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL CHECK (round > 0), -- 1 is the opening offer of the buyer
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    side TEXT NOT NULL CHECK (side IN ('buyer', 'store')),
+    price NUMERIC(10, 2) NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (offer_id, round)
+);
+
+-- Existing offers open their thread with the price of the buyer
+INSERT INTO offer_rounds (offer_id, round, user_id, side, price, currency, created_at)
+SELECT id, 1, user_id, 'buyer', price, currency, created_at FROM offers;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_offers_open_expires_at;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Index for the worker expiring offers still under negotiation
+CREATE INDEX idx_offers_open_expires_at ON offers(expires_at) WHERE status IN ('pending', 'countered');
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_offers_store_id_created_at;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Index for the store offer inbox, newest first
+CREATE INDEX idx_offers_store_id_created_at ON offers(store_id, created_at DESC);
\ No newline at end of file
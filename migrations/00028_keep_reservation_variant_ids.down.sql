@@ -0,0 +1,5 @@
+UPDATE stock_reservations SET variant_id = NULL
+WHERE variant_id IS NOT NULL AND variant_id NOT IN (SELECT id FROM product_variants);
+
+ALTER TABLE stock_reservations ADD CONSTRAINT stock_reservations_variant_id_fkey
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL;
\ No newline at end of file
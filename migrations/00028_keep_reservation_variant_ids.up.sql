@@ -0,0 +1,3 @@
+-- Reservations keep the id of a deleted variant, so that releasing them
+-- doesn't credit the variant stock to the product
+ALTER TABLE stock_reservations DROP CONSTRAINT stock_reservations_variant_id_fkey;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS store_members;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Databases migrated before this table had its own migration already have it
+CREATE TABLE IF NOT EXISTS store_members (
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'staff', -- owner, staff
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (store_id, user_id)
+);
+
+-- Index on user_id
+CREATE INDEX IF NOT EXISTS idx_store_members_user_id ON store_members(user_id);
\ No newline at end of file
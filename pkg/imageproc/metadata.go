@@ -0,0 +1,218 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const exifOrientationTag = 0x0112
+
+// StripMetadata removes EXIF, XMP and text metadata (including GPS location)
+// from an encoded image without re-encoding it and reports whether anything
+// was removed. A JPEG keeps a minimal EXIF block with only its orientation so
+// it is still displayed upright. Data of any other content type, or that
+// can't be parsed, is returned unchanged.
+func StripMetadata(data []byte, contentType string) ([]byte, bool) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	default:
+		return data, false
+	}
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG or 1 if it has none.
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	walkJPEG(data, func(marker byte, segment []byte) bool {
+		if marker == 0xE1 {
+			if o := exifOrientation(segment[4:]); o != 0 {
+				orientation = o
+			}
+		}
+		return true
+	})
+	return orientation
+}
+
+// walkJPEG calls fn with every marker segment before the scan data. The
+// segment slice includes the marker and length bytes.
+func walkJPEG(data []byte, fn func(marker byte, segment []byte) bool) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return -1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) && data[pos] == 0xFF {
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return pos
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return -1
+		}
+		if !fn(marker, data[pos:end]) {
+			return -1
+		}
+		pos = end
+	}
+
+	return -1
+}
+
+func stripJPEG(data []byte) ([]byte, bool) {
+	orientation := jpegOrientation(data)
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	if orientation != 1 {
+		out = append(out, orientationSegment(orientation)...)
+	}
+
+	stripped := false
+	scanStart := walkJPEG(data, func(marker byte, segment []byte) bool {
+		// APP1 holds EXIF and XMP, APP13 holds IPTC, 0xFE is a comment
+		if marker == 0xE1 || marker == 0xED || marker == 0xFE {
+			stripped = true
+		} else {
+			out = append(out, segment...)
+		}
+		return true
+	})
+	if scanStart < 0 || !stripped {
+		return data, false
+	}
+
+	return append(out, data[scanStart:]...), true
+}
+
+// exifOrientation reads the orientation tag from an APP1 payload.
+func exifOrientation(payload []byte) int {
+	if len(payload) < 14 || !bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+		return 0
+	}
+	tiff := payload[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 0
+}
+
+// orientationSegment builds an APP1 segment with a single orientation tag.
+func orientationSegment(orientation int) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(42))
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(8))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(1))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(exifOrientationTag))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(3)) // SHORT
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(1))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(orientation))
+	_ = binary.Write(&tiff, binary.BigEndian, uint16(0))
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+
+	return append(segment, payload...)
+}
+
+func stripPNG(data []byte) ([]byte, bool) {
+	signature := []byte("\x89PNG\r\n\x1a\n")
+	if !bytes.HasPrefix(data, signature) {
+		return data, false
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, signature...)
+	stripped := false
+	pos := len(signature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return data, false
+		}
+		switch string(data[pos+4 : pos+8]) {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+			stripped = true
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	if !stripped {
+		return data, false
+	}
+
+	return out, true
+}
+
+func stripWebP(data []byte) ([]byte, bool) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return data, false
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	stripped := false
+	pos := 12
+	for pos+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size%2
+		if end > len(data) {
+			end = len(data)
+		}
+		switch fourCC := string(data[pos : pos+4]); fourCC {
+		case "EXIF", "XMP ":
+			stripped = true
+		case "VP8X":
+			chunk := append([]byte(nil), data[pos:end]...)
+			if len(chunk) > 8 {
+				// Clear the EXIF (0x08) and XMP (0x04) presence flags
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	if !stripped {
+		return data, false
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+
+	return out, true
+}
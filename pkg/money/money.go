@@ -0,0 +1,291 @@
+// Package money provides an exact monetary amount stored as an integer number
+// of minor units (e.g. kopecks or cents) together with an ISO 4217 currency.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for amounts that don't specify a currency
+const DefaultCurrency = "RUB"
+
+// minorDigits lists the currencies that don't have two decimal places. Price
+// columns are NUMERIC(10, 2), so currencies with three decimal places such as
+// KWD and BHD are kept with two rather than losing the third on every write.
+var minorDigits = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+type Money struct {
+	// Amount is the value in minor units of Currency
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a decimal amount such as "12.5" or "-0.015" in major units.
+// Amounts with more decimal places than the currency has are rounded half
+// away from zero, the same way every other operation of the package rounds.
+func Parse(s, currency string) (Money, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	s = strings.TrimSpace(s)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || strings.Contains(s, "/") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(scale(currency)))
+	amount, ok := roundRat(r)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, s)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// FromFloat converts a float amount in major units using its shortest decimal
+// representation, so 0.1 + 0.2 style binary errors don't leak into the result.
+func FromFloat(f float64, currency string) (Money, error) {
+	return Parse(strconv.FormatFloat(f, 'f', -1, 64), currency)
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 code, e.g. "USD".
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Digits returns the number of decimal places of the currency.
+func Digits(currency string) int {
+	if digits, ok := minorDigits[strings.ToUpper(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Cmp compares two amounts of the same currency and returns -1, 0 or +1. It
+// panics if the currencies differ, Convert one of the amounts first.
+func (m Money) Cmp(other Money) int {
+	m.mustMatch(other)
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add panics if the currencies differ, like Cmp.
+func (m Money) Add(other Money) Money {
+	m.mustMatch(other)
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}
+}
+
+// Sub panics if the currencies differ, like Cmp.
+func (m Money) Sub(other Money) Money {
+	m.mustMatch(other)
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}
+}
+
+// Mul multiplies the amount by an exact factor such as a quantity or a rate,
+// rounding half away from zero.
+func (m Money) Mul(factor *big.Rat) Money {
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), factor)
+	amount, _ := roundRat(r)
+	return Money{Amount: amount, Currency: m.Currency}
+}
+
+// WithCurrency returns the same decimal amount in another currency, rounded to
+// its number of decimal places. It relabels the amount, use Convert to apply
+// an exchange rate.
+func (m Money) WithCurrency(currency string) Money {
+	from, to := Digits(m.currency()), Digits(currency)
+	if from == to {
+		return Money{Amount: m.Amount, Currency: currency}
+	}
+
+	factor := new(big.Rat).SetFrac(pow10(to), pow10(from))
+	converted := m.Mul(factor)
+	converted.Currency = currency
+	return converted
+}
+
+// Convert exchanges the amount into currency at rate, the price of one unit
+// of m's currency in the target currency, rounding half away from zero.
+func (m Money) Convert(currency string, rate *big.Rat) Money {
+	factor := new(big.Rat).Mul(rate, new(big.Rat).SetFrac(pow10(Digits(currency)), pow10(Digits(m.currency()))))
+	converted := m.Mul(factor)
+	converted.Currency = currency
+	return converted
+}
+
+// Decimal formats the amount in major units with the currency's number of
+// decimal places, e.g. "12.50".
+func (m Money) Decimal() string {
+	digits := Digits(m.currency())
+
+	abs := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	if digits == 0 {
+		if m.Amount < 0 {
+			return "-" + abs
+		}
+		return abs
+	}
+
+	if len(abs) <= digits {
+		abs = strings.Repeat("0", digits-len(abs)+1) + abs
+	}
+	decimal := abs[:len(abs)-digits] + "." + abs[len(abs)-digits:]
+	if m.Amount < 0 {
+		return "-" + decimal
+	}
+	return decimal
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.currency()
+}
+
+// MarshalJSON writes the amount as a JSON number in major units, the format
+// prices had before they became Money.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding a decimal amount.
+// The currency is left to the caller and defaults to DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 1 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+		}
+		data = []byte(s)
+	}
+
+	parsed, err := Parse(string(data), m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount in a NUMERIC column.
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+// Scan reads the amount from a NUMERIC column. The column holds no currency,
+// so it is kept from m or defaults to DefaultCurrency.
+func (m *Money) Scan(src interface{}) error {
+	var (
+		parsed Money
+		err    error
+	)
+	switch v := src.(type) {
+	case nil:
+		*m = Money{Currency: m.currency()}
+		return nil
+	case string:
+		parsed, err = Parse(v, m.Currency)
+	case []byte:
+		parsed, err = Parse(string(v), m.Currency)
+	case int64:
+		parsed, err = Parse(strconv.FormatInt(v, 10), m.Currency)
+	case float64:
+		parsed, err = FromFloat(v, m.Currency)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidAmount, src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// GormDataType makes gorm treat Money as a single numeric column.
+func (Money) GormDataType() string {
+	return "numeric"
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// mustMatch panics unless both amounts are in the same currency, mixing them
+// is a programming error rather than bad input.
+func (m Money) mustMatch(other Money) {
+	if m.currency() != other.currency() {
+		panic(fmt.Sprintf("money: mismatched currencies %s and %s", m.currency(), other.currency()))
+	}
+}
+
+func scale(currency string) *big.Int {
+	return pow10(Digits(currency))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat rounds r to an integer half away from zero.
+func roundRat(r *big.Rat) (int64, bool) {
+	num := new(big.Int).Abs(r.Num())
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	if !quo.IsInt64() {
+		return 0, false
+	}
+	return quo.Int64(), true
+}
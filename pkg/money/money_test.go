@@ -0,0 +1,245 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{name: "integer", input: "12", currency: "USD", want: New(1200, "USD")},
+		{name: "two decimals", input: "12.34", currency: "USD", want: New(1234, "USD")},
+		{name: "one decimal", input: "12.5", currency: "USD", want: New(1250, "USD")},
+		{name: "surrounding spaces", input: " 7.10 ", currency: "USD", want: New(710, "USD")},
+		{name: "default currency", input: "1.5", want: New(150, DefaultCurrency)},
+		{name: "rounds half up", input: "0.015", currency: "USD", want: New(2, "USD")},
+		{name: "rounds down", input: "0.014", currency: "USD", want: New(1, "USD")},
+		{name: "negative rounds away from zero", input: "-0.015", currency: "USD", want: New(-2, "USD")},
+		{name: "zero digit currency", input: "1500", currency: "JPY", want: New(1500, "JPY")},
+		{name: "zero digit currency rounds", input: "1500.5", currency: "JPY", want: New(1501, "JPY")},
+		{name: "three digit currency keeps two", input: "1.234", currency: "KWD", want: New(123, "KWD")},
+		{name: "empty", input: "", currency: "USD", wantErr: true},
+		{name: "not a number", input: "abc", currency: "USD", wantErr: true},
+		{name: "fraction", input: "1/3", currency: "USD", wantErr: true},
+		{name: "out of range", input: "1e30", currency: "USD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.currency)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidAmount", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  int64
+	}{
+		{input: 0.1 + 0.2, want: 30},
+		{input: 19.99, want: 1999},
+		{input: 2.675, want: 268},
+	}
+
+	for _, tt := range tests {
+		got, err := FromFloat(tt.input, "USD")
+		if err != nil {
+			t.Fatalf("FromFloat(%v) unexpected error: %v", tt.input, err)
+		}
+		if got.Amount != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.input, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		into    Money
+		src     interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "string", src: "12.50", want: New(1250, DefaultCurrency)},
+		{name: "bytes", src: []byte("0.99"), want: New(99, DefaultCurrency)},
+		{name: "int64", src: int64(3), want: New(300, DefaultCurrency)},
+		{name: "float64", src: 4.2, want: New(420, DefaultCurrency)},
+		{name: "keeps currency", into: Money{Currency: "JPY"}, src: "1500.00", want: New(1500, "JPY")},
+		{name: "null keeps currency", into: Money{Amount: 5, Currency: "USD"}, src: nil, want: New(0, "USD")},
+		{name: "null defaults currency", src: nil, want: New(0, DefaultCurrency)},
+		{name: "invalid string", src: "x", wantErr: true},
+		{name: "unsupported type", src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.into
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("Scan(%v) error = %v, want ErrInvalidAmount", tt.src, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan(%v) unexpected error: %v", tt.src, err)
+			}
+			if got != tt.want {
+				t.Errorf("Scan(%v) = %+v, want %+v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	for _, m := range []Money{New(1250, "USD"), New(-7, "USD"), New(1500, "JPY"), New(5, "RUB")} {
+		value, err := m.Value()
+		if err != nil {
+			t.Fatalf("%v.Value() unexpected error: %v", m, err)
+		}
+
+		got := Money{Currency: m.Currency}
+		if err := got.Scan(value); err != nil {
+			t.Fatalf("Scan(%v) unexpected error: %v", value, err)
+		}
+		if got != m {
+			t.Errorf("round trip of %+v = %+v", m, got)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(1250, "USD"), want: "12.50"},
+		{money: New(5, "USD"), want: "0.05"},
+		{money: New(-5, "USD"), want: "-0.05"},
+		{money: New(0, "USD"), want: "0.00"},
+		{money: New(1500, "JPY"), want: "1500"},
+		{money: New(-1500, "JPY"), want: "-1500"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("%+v.Decimal() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestWithCurrency(t *testing.T) {
+	tests := []struct {
+		money    Money
+		currency string
+		want     Money
+	}{
+		{money: New(1250, "USD"), currency: "EUR", want: New(1250, "EUR")},
+		{money: New(150050, "RUB"), currency: "JPY", want: New(1501, "JPY")},
+		{money: New(1500, "JPY"), currency: "USD", want: New(150000, "USD")},
+		{money: Money{Amount: 100}, currency: "USD", want: New(100, "USD")},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.WithCurrency(tt.currency); got != tt.want {
+			t.Errorf("%+v.WithCurrency(%s) = %+v, want %+v", tt.money, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestMulAndConvert(t *testing.T) {
+	if got := New(333, "USD").Mul(big.NewRat(1, 2)); got != New(167, "USD") {
+		t.Errorf("Mul rounds half away from zero, got %+v", got)
+	}
+	if got := New(-333, "USD").Mul(big.NewRat(1, 2)); got != New(-167, "USD") {
+		t.Errorf("Mul rounds negative half away from zero, got %+v", got)
+	}
+	if got := New(1000, "USD").Convert("JPY", big.NewRat(15025, 100)); got != New(1503, "JPY") {
+		t.Errorf("Convert(JPY) = %+v, want 1503 JPY", got)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := New(1250, "USD"), New(250, "USD")
+
+	if got := a.Add(b); got != New(1500, "USD") {
+		t.Errorf("Add = %+v", got)
+	}
+	if got := a.Sub(b); got != New(1000, "USD") {
+		t.Errorf("Sub = %+v", got)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(a) != 0 {
+		t.Errorf("Cmp order is wrong")
+	}
+	if (Money{Amount: 1}).Cmp(New(1, DefaultCurrency)) != 0 {
+		t.Errorf("an amount without a currency is in DefaultCurrency")
+	}
+}
+
+func TestCurrencyMismatchPanics(t *testing.T) {
+	usd, eur := New(100, "USD"), New(100, "EUR")
+
+	for name, op := range map[string]func(){
+		"Add": func() { usd.Add(eur) },
+		"Sub": func() { usd.Sub(eur) },
+		"Cmp": func() { usd.Cmp(eur) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s of USD and EUR did not panic", name)
+				}
+			}()
+			op()
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: `12.5`, want: New(1250, DefaultCurrency)},
+		{input: `"12.5"`, want: New(1250, DefaultCurrency)},
+		{input: `null`, want: Money{}},
+		{input: `"abc"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		err := got.UnmarshalJSON([]byte(tt.input))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("UnmarshalJSON(%s) expected an error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("UnmarshalJSON(%s) unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/PosokhovVadim/stawberry/internal/config"
 
@@ -20,6 +23,11 @@ import (
 type BucketBasics struct {
 	BucketName string
 	S3Client   *s3.Client
+	// Endpoint is the base URL used to build public object URLs
+	Endpoint string
+	// Public reports whether objects can be read without a presigned URL
+	Public     bool
+	PresignTTL time.Duration
 }
 
 func ObjectStorageConn(cfg *config.Config) *BucketBasics {
@@ -42,7 +50,13 @@ func ObjectStorageConn(cfg *config.Config) *BucketBasics {
 	if err != nil {
 		log.Fatal(err)
 	}
-	return &BucketBasics{cfg.BucketName, s3.NewFromConfig(sdkCfg)}
+	return &BucketBasics{
+		BucketName: cfg.BucketName,
+		S3Client:   s3.NewFromConfig(sdkCfg),
+		Endpoint:   cfg.URL,
+		Public:     cfg.BucketPublic,
+		PresignTTL: cfg.PresignTTL,
+	}
 }
 
 func (basics BucketBasics) UploadFileWithPresignedURL(ctx context.Context, objectKey string, file io.Reader) error {
@@ -109,3 +123,66 @@ func (basics BucketBasics) DownloadFile(ctx context.Context, objectKey string) (
 	}
 	return body, err
 }
+
+func (basics BucketBasics) UploadFile(
+	ctx context.Context,
+	objectKey, contentType string,
+	file io.Reader,
+	size int64,
+) error {
+	_, err := basics.S3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(basics.BucketName),
+		Key:           aws.String(objectKey),
+		Body:          file,
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		log.Printf("Couldn't upload object %v:%v. Here's why: %v\n", basics.BucketName, objectKey, err)
+		return err
+	}
+
+	return nil
+}
+
+func (basics BucketBasics) DeleteFile(ctx context.Context, objectKey string) error {
+	_, err := basics.S3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(basics.BucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		log.Printf("Couldn't delete object %v:%v. Here's why: %v\n", basics.BucketName, objectKey, err)
+		return err
+	}
+
+	return nil
+}
+
+// ObjectURL returns a URL the object can be fetched from. Objects of a private
+// bucket get a presigned URL that is valid for PresignTTL.
+func (basics BucketBasics) ObjectURL(ctx context.Context, objectKey string) (string, error) {
+	if basics.Public {
+		return basics.publicURL(objectKey)
+	}
+
+	presignClient := s3.NewPresignClient(basics.S3Client)
+	presignResult, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(basics.BucketName),
+		Key:    aws.String(objectKey),
+	}, s3.WithPresignExpires(basics.PresignTTL))
+	if err != nil {
+		log.Printf("Couldn't get presigned URL for %v. Here's why: %v\n", objectKey, err)
+		return "", err
+	}
+
+	return presignResult.URL, nil
+}
+
+func (basics BucketBasics) publicURL(objectKey string) (string, error) {
+	base, err := url.Parse(strings.TrimSuffix(basics.Endpoint, "/"))
+	if err != nil {
+		return "", err
+	}
+
+	return base.JoinPath(basics.BucketName, objectKey).String(), nil
+}
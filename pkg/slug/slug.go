@@ -0,0 +1,56 @@
+// Package slug builds human-readable URL identifiers from names, Cyrillic
+// letters are transliterated to latin ones.
+package slug
+
+import "strings"
+
+// MaxLength is the longest slug in bytes. The slugify function of the slugs
+// migration follows the same rules, keep the two in sync.
+const MaxLength = 80
+
+// cyrillic maps lowercase Russian and Ukrainian letters to their latin
+// transliteration, the hard and soft signs are dropped.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+}
+
+// Make returns the slug of s: lowercase latin letters and digits, with every
+// other run of characters replaced by a single hyphen. The result is empty
+// when s has nothing to keep.
+func Make(s string) string {
+	var b strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(s) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			part = string(r)
+		default:
+			var ok bool
+			if part, ok = cyrillic[r]; !ok {
+				separate = true
+				continue
+			}
+		}
+		if part == "" {
+			continue
+		}
+
+		if separate && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		separate = false
+		b.WriteString(part)
+	}
+
+	result := b.String()
+	if len(result) > MaxLength {
+		result = strings.TrimRight(result[:MaxLength], "-")
+	}
+	return result
+}